@@ -0,0 +1,309 @@
+// Package archive writes an organisation's folder tree to a tar or zip archive for offline backup,
+// and restores it into a driver.
+//
+// An archive holds a manifest.json listing the folders, which is what gets restored,
+// along with an empty directory entry for each folder beneath folders/ so the tree can be browsed.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+)
+
+// Format is the container format of an archive
+type Format string
+
+const (
+	Tar Format = "tar"
+	Zip Format = "zip"
+)
+
+// ManifestVersion is the version of the manifest written by Export
+const ManifestVersion = 1
+
+const manifestName = "manifest.json"
+
+const foldersDir = "folders/"
+
+// ErrInvalidArchive is returned when an archive lacks a readable manifest
+var ErrInvalidArchive = errors.New("invalid folder archive")
+
+// Manifest describes the folders held in an archive
+type Manifest struct {
+	Version    int             `json:"version"`
+	OrgID      uuid.UUID       `json:"org_id"`
+	Root       string          `json:"root,omitempty"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Folders    []folder.Folder `json:"folders"`
+}
+
+// Export writes the folders of an organisation to an archive.
+// If root is given, only the folder at that path and its descendants are written.
+func Export(w io.Writer, format Format, d folder.IDriver, orgID uuid.UUID, root string) error {
+	folders := d.GetFoldersByOrgID(orgID)
+	if root != "" {
+		folders = subtree(folders, root)
+		if len(folders) == 0 {
+			return fmt.Errorf("%w: %q", folder.ErrFolderNotFound, root)
+		}
+	}
+
+	manifest, err := json.MarshalIndent(Manifest{
+		Version:    ManifestVersion,
+		OrgID:      orgID,
+		Root:       root,
+		ExportedAt: time.Now().UTC(),
+		Folders:    folders,
+	}, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	dirs := make([]string, 0, len(folders))
+	for _, f := range folders {
+		if strings.Contains(f.Paths, "/") {
+			return fmt.Errorf("%w: %q", folder.ErrInvalidPath, f.Paths)
+		}
+		dirs = append(dirs, foldersDir+strings.ReplaceAll(f.Paths, ".", "/")+"/")
+	}
+
+	switch format {
+	case Tar:
+		return writeTar(w, manifest, dirs)
+	case Zip:
+		return writeZip(w, manifest, dirs)
+	}
+	return fmt.Errorf("unknown archive format %q", format)
+}
+
+// ReadManifest reads the manifest from an archive
+func ReadManifest(r io.Reader, format Format) (Manifest, error) {
+	var manifest Manifest
+	var b []byte
+	var err error
+
+	switch format {
+	case Tar:
+		b, err = readTarManifest(r)
+	case Zip:
+		b, err = readZipManifest(r)
+	default:
+		return manifest, fmt.Errorf("unknown archive format %q", format)
+	}
+	if err != nil {
+		return manifest, err
+	}
+
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return manifest, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	if manifest.Version != ManifestVersion {
+		return manifest, fmt.Errorf("%w: unsupported manifest version %d", ErrInvalidArchive, manifest.Version)
+	}
+
+	return manifest, nil
+}
+
+// Import restores the folders in an archive into an organisation, beneath the folder at dst,
+// or as root folders if dst is empty. The archived root keeps its name, so exporting "a.b"
+// and importing beneath "x" creates "x.b".
+// Nothing is restored unless every folder can be. The import is first tried against a copy of the organisation,
+// within any folder.Limits d reports, and should d still refuse a folder, those already restored are deleted again.
+func Import(r io.Reader, format Format, d folder.IStatefulDriver, orgID uuid.UUID, dst string) ([]folder.Folder, error) {
+	manifest, err := ReadManifest(r, format)
+	if err != nil {
+		return []folder.Folder{}, err
+	}
+
+	var base string = folder.ParentPath(manifest.Root)
+	folders := parentsFirst(manifest.Folders)
+	creates := make([][2]string, 0, len(folders))
+	for _, f := range folders {
+		if folder.JoinPath(folder.ParentPath(f.Paths), f.Name) != f.Paths {
+			return []folder.Folder{}, fmt.Errorf("%w: %q is not named %q", ErrInvalidArchive, f.Paths, f.Name)
+		}
+		if manifest.Root != "" && f.Paths != manifest.Root && !folder.IsDescendantPath(manifest.Root, f.Paths) {
+			return []folder.Folder{}, fmt.Errorf("%w: %q is outside of root %q", ErrInvalidArchive, f.Paths, manifest.Root)
+		}
+
+		var path string = f.Paths
+		if base != "" {
+			path = strings.TrimPrefix(path, base+".")
+		}
+		creates = append(creates, [2]string{f.Name, folder.ParentPath(folder.JoinPath(dst, path))})
+	}
+
+	// A dry run against a copy of the organisation catches most failures before d is touched
+	scratch, err := folder.NewLimitedDriver(folder.NewDriver, d.GetFoldersByOrgID(orgID), folder.LimitsOf(d))
+	if err != nil {
+		return []folder.Folder{}, err
+	}
+	for _, c := range creates {
+		if _, err := scratch.CreateFolder(orgID, c[0], c[1]); err != nil {
+			return []folder.Folder{}, fmt.Errorf("restore %q beneath %q: %w", c[0], c[1], err)
+		}
+	}
+
+	// Limits d enforces without reporting them, such as those of a wrapped driver, are only met here
+	var result []folder.Folder = []folder.Folder{}
+	created := make([]string, 0, len(creates))
+	for _, c := range creates {
+		if result, err = d.CreateFolder(orgID, c[0], c[1]); err != nil {
+			undo(d, orgID, created)
+			return []folder.Folder{}, fmt.Errorf("restore %q beneath %q: %w", c[0], c[1], err)
+		}
+		created = append(created, folder.JoinPath(c[1], c[0]))
+	}
+
+	return result, nil
+}
+
+// undo deletes the folders an import created, deleting only the topmost so descendants go with them
+func undo(d folder.IStatefulDriver, orgID uuid.UUID, created []string) {
+	paths := map[string]bool{}
+	for _, path := range created {
+		paths[path] = true
+	}
+
+	for _, path := range created {
+		if !paths[folder.ParentPath(path)] {
+			d.DeleteFolder(orgID, path)
+		}
+	}
+}
+
+// parentsFirst orders folders so each comes after its parent, otherwise keeping their order
+func parentsFirst(folders []folder.Folder) []folder.Folder {
+	paths := map[string]bool{}
+	for _, f := range folders {
+		paths[f.Paths] = true
+	}
+
+	result := make([]folder.Folder, 0, len(folders))
+	placed := map[string]bool{}
+	for len(result) < len(folders) {
+		var progressed bool = false
+		for _, f := range folders {
+			var parent string = folder.ParentPath(f.Paths)
+			if !placed[f.Paths] && (!paths[parent] || placed[parent]) {
+				result = append(result, f)
+				placed[f.Paths] = true
+				progressed = true
+			}
+		}
+
+		// Only duplicated paths can stall, and the first of each is already placed
+		if !progressed {
+			break
+		}
+	}
+	return result
+}
+
+// subtree filters folders to the one at root and its descendants
+func subtree(folders []folder.Folder, root string) []folder.Folder {
+	result := []folder.Folder{}
+	for _, f := range folders {
+		if f.Paths == root || folder.IsDescendantPath(root, f.Paths) {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+func writeTar(w io.Writer, manifest []byte, dirs []string) error {
+	tw := tar.NewWriter(w)
+	var modTime time.Time = time.Now()
+
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     manifestName,
+		Mode:     0o644,
+		Size:     int64(len(manifest)),
+		ModTime:  modTime,
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifest); err != nil {
+		return err
+	}
+
+	if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: foldersDir, Mode: 0o755, ModTime: modTime}); err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: dir, Mode: 0o755, ModTime: modTime}); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+func writeZip(w io.Writer, manifest []byte, dirs []string) error {
+	zw := zip.NewWriter(w)
+
+	mw, err := zw.Create(manifestName)
+	if err != nil {
+		return err
+	}
+	if _, err := mw.Write(manifest); err != nil {
+		return err
+	}
+
+	// Names ending in a slash are written as directory entries
+	for _, dir := range append([]string{foldersDir}, dirs...) {
+		if _, err := zw.Create(dir); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func readTarManifest(r io.Reader) ([]byte, error) {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: no %s", ErrInvalidArchive, manifestName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+
+		if header.Name == manifestName {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+func readZipManifest(r io.Reader) ([]byte, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+
+	file, err := zr.Open(manifestName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: no %s", ErrInvalidArchive, manifestName)
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
@@ -0,0 +1,252 @@
+package archive_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/folder/archive"
+	"github.com/georgechieng-sc/interns-2022/folder/trash"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+var sampleFolders = []folder.Folder{
+	{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+	{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+	{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+	{Name: "delta", OrgId: validOrgId, Paths: "alpha.delta"},
+	{Name: "echo", OrgId: validOrgId, Paths: "echo"},
+	{Name: "foxtrot", OrgId: otherOrgId, Paths: "foxtrot"},
+}
+
+func Test_archive_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := [...]struct {
+		testName string
+		format   archive.Format
+		root     string
+		dst      string
+		existing []folder.Folder
+		want     []folder.Folder
+	}{
+		{
+			testName: "Whole organisation as tar",
+			format:   archive.Tar,
+			want:     sampleFolders[:5],
+		},
+		{
+			testName: "Whole organisation as zip",
+			format:   archive.Zip,
+			want:     sampleFolders[:5],
+		},
+		{
+			testName: "Subtree restored beneath a destination",
+			format:   archive.Zip,
+			root:     "alpha.bravo",
+			dst:      "restored",
+			existing: []folder.Folder{{Name: "restored", OrgId: otherOrgId, Paths: "restored"}},
+			want: []folder.Folder{
+				{Name: "restored", OrgId: otherOrgId, Paths: "restored"},
+				{Name: "bravo", OrgId: otherOrgId, Paths: "restored.bravo"},
+				{Name: "charlie", OrgId: otherOrgId, Paths: "restored.bravo.charlie"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, archive.Export(&buf, tt.format, folder.NewDriver(sampleFolders), validOrgId, tt.root))
+
+			orgId := validOrgId
+			if tt.existing != nil {
+				orgId = otherOrgId
+			}
+			d := folder.NewDriver(tt.existing)
+			_, err := archive.Import(&buf, tt.format, d, orgId, tt.dst)
+			assert.NoError(t, err, tt.testName)
+			assert.Equal(t, tt.want, d.GetFoldersByOrgID(orgId), tt.testName)
+		})
+	}
+}
+
+func Test_archive_DirectoryEntries(t *testing.T) {
+	t.Parallel()
+
+	d := folder.NewDriver(sampleFolders)
+	want := []string{
+		"manifest.json",
+		"folders/",
+		"folders/alpha/bravo/",
+		"folders/alpha/bravo/charlie/",
+	}
+
+	var tarBuf bytes.Buffer
+	require.NoError(t, archive.Export(&tarBuf, archive.Tar, d, validOrgId, "alpha.bravo"))
+	tarNames := []string{}
+	tr := tar.NewReader(&tarBuf)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		tarNames = append(tarNames, header.Name)
+	}
+	assert.Equal(t, want, tarNames)
+
+	var zipBuf bytes.Buffer
+	require.NoError(t, archive.Export(&zipBuf, archive.Zip, d, validOrgId, "alpha.bravo"))
+	zr, err := zip.NewReader(bytes.NewReader(zipBuf.Bytes()), int64(zipBuf.Len()))
+	require.NoError(t, err)
+	zipNames := []string{}
+	for _, f := range zr.File {
+		zipNames = append(zipNames, f.Name)
+	}
+	assert.Equal(t, want, zipNames)
+}
+
+func Test_archive_Manifest(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, archive.Export(&buf, archive.Tar, folder.NewDriver(sampleFolders), validOrgId, "alpha.bravo"))
+
+	manifest, err := archive.ReadManifest(&buf, archive.Tar)
+	assert.NoError(t, err)
+	assert.Equal(t, archive.ManifestVersion, manifest.Version)
+	assert.Equal(t, validOrgId, manifest.OrgID)
+	assert.Equal(t, "alpha.bravo", manifest.Root)
+	assert.Equal(t, sampleFolders[1:3], manifest.Folders)
+	assert.False(t, manifest.ExportedAt.IsZero())
+}
+
+func Test_archive_Errors(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	err := archive.Export(&buf, archive.Tar, folder.NewDriver(sampleFolders), validOrgId, "missing")
+	assert.ErrorIs(t, err, folder.ErrFolderNotFound, "Export of a missing root")
+
+	_, err = archive.Import(bytes.NewReader([]byte("not an archive")), archive.Zip, folder.NewDriver(nil), validOrgId, "")
+	assert.ErrorIs(t, err, archive.ErrInvalidArchive, "Import of a file which is not an archive")
+
+	// Restoring over existing folders fails without restoring any of them
+	buf.Reset()
+	require.NoError(t, archive.Export(&buf, archive.Tar, folder.NewDriver(sampleFolders), validOrgId, ""))
+	existing := []folder.Folder{{Name: "echo", OrgId: validOrgId, Paths: "echo"}}
+	d := folder.NewDriver(existing)
+	_, err = archive.Import(&buf, archive.Tar, d, validOrgId, "")
+	assert.ErrorIs(t, err, folder.ErrFolderExists, "Import over an existing folder")
+	assert.Equal(t, existing, d.GetFoldersByOrgID(validOrgId))
+
+	buf.Reset()
+	require.NoError(t, archive.Export(&buf, archive.Tar, folder.NewDriver(sampleFolders), validOrgId, ""))
+	_, err = archive.Import(&buf, archive.Tar, folder.NewDriver(nil), validOrgId, "missing")
+	assert.ErrorIs(t, err, folder.ErrFolderNotFound, "Import beneath a missing destination")
+}
+
+func Test_archive_Import_Limits(t *testing.T) {
+	t.Parallel()
+
+	existing := []folder.Folder{{Name: "x", OrgId: validOrgId, Paths: "x"}}
+
+	tests := [...]struct {
+		testName string
+		limits   folder.Limits
+		dst      string
+		err      error
+	}{
+		{testName: "Within every limit", limits: folder.Limits{MaxFolders: 6, MaxDepth: 4, MaxChildren: 2}, dst: "x"},
+		{testName: "Too many folders", limits: folder.Limits{MaxFolders: 5}, err: folder.ErrTooManyFolders},
+		{testName: "Too deep beneath the destination", limits: folder.Limits{MaxDepth: 3}, dst: "x", err: folder.ErrTooDeep},
+		{testName: "Too many children", limits: folder.Limits{MaxChildren: 1}, err: folder.ErrTooManyChildren},
+		{testName: "Path too long", limits: folder.Limits{MaxPathLength: 20}, dst: "x", err: folder.ErrPathTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			require.NoError(t, archive.Export(&buf, archive.Zip, folder.NewDriver(sampleFolders), validOrgId, ""))
+			d, err := folder.NewLimitedDriver(folder.NewDriver, append([]folder.Folder{}, existing...), tt.limits)
+			require.NoError(t, err)
+
+			_, err = archive.Import(bytes.NewReader(buf.Bytes()), archive.Zip, d, validOrgId, tt.dst)
+			if tt.err == nil {
+				assert.NoError(t, err)
+				assert.Len(t, d.GetFoldersByOrgID(validOrgId), 6)
+				return
+			}
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, existing, d.GetFoldersByOrgID(validOrgId), "A rejected import restores nothing")
+		})
+	}
+}
+
+func Test_archive_Import_Undone(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, archive.Export(&buf, archive.Tar, folder.NewDriver(sampleFolders), validOrgId, ""))
+
+	// The trash driver enforces the limits of the driver it wraps without reporting them,
+	// so the dry run passes and the fifth folder is refused part way through the import
+	existing := []folder.Folder{{Name: "x", OrgId: validOrgId, Paths: "x"}}
+	limited, err := folder.NewLimitedDriver(folder.NewDriver, append([]folder.Folder{}, existing...), folder.Limits{MaxFolders: 5})
+	require.NoError(t, err)
+	d := trash.NewDriver(limited, trash.Config{})
+
+	_, err = archive.Import(&buf, archive.Tar, d, validOrgId, "")
+	assert.ErrorIs(t, err, folder.ErrTooManyFolders)
+	assert.Equal(t, existing, d.GetFoldersByOrgID(validOrgId), "Folders restored before the failure are deleted again")
+}
+
+func Test_archive_Import_InvalidManifest(t *testing.T) {
+	t.Parallel()
+
+	tests := [...]struct {
+		testName string
+		root     string
+		folders  []folder.Folder
+	}{
+		{
+			testName: "Name is not the last label of the path",
+			folders:  []folder.Folder{{Name: "alpha", OrgId: validOrgId, Paths: "alpha"}, {Name: "zulu", OrgId: validOrgId, Paths: "alpha.bravo"}},
+		},
+		{
+			testName: "Folder outside of the root",
+			root:     "alpha.bravo",
+			folders:  []folder.Folder{{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"}, {Name: "delta", OrgId: validOrgId, Paths: "alpha.delta"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			manifest, err := json.Marshal(archive.Manifest{Version: archive.ManifestVersion, OrgID: validOrgId, Root: tt.root, Folders: tt.folders})
+			require.NoError(t, err)
+			var buf bytes.Buffer
+			zw := zip.NewWriter(&buf)
+			w, err := zw.Create("manifest.json")
+			require.NoError(t, err)
+			_, err = w.Write(manifest)
+			require.NoError(t, err)
+			require.NoError(t, zw.Close())
+
+			d := folder.NewDriver(nil)
+			_, err = archive.Import(&buf, archive.Zip, d, validOrgId, "")
+			assert.ErrorIs(t, err, archive.ErrInvalidArchive)
+			assert.Empty(t, d.GetFoldersByOrgID(validOrgId))
+		})
+	}
+}
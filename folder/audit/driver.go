@@ -0,0 +1,161 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+)
+
+// Driver is a folder.IStatefulDriver acting for one actor, which writes a Record for each mutation that changes folders.
+// It learns what changed from the events of the driver it wraps, so every mutation of that driver must be made
+// through a Driver, or the records may count folders changed by others. Drivers for other actors are made with As.
+//
+// A mutation whose record cannot be written is still made, returning the folders along with the error.
+type Driver struct {
+	*auditor
+	actor string
+}
+
+// auditor is shared by the Drivers of every actor over the same driver
+type auditor struct {
+	mu     sync.Mutex // held through each mutation, so it collects only its own events
+	driver folder.IObservableDriver
+	sink   Sink
+	cancel func()
+
+	eventsMu   sync.Mutex
+	collecting bool
+	events     []folder.Event
+}
+
+// NewDriver creates a driver acting for actor over d, writing records to sink. Close it once done.
+func NewDriver(d folder.IObservableDriver, sink Sink, actor string) *Driver {
+	a := &auditor{driver: d, sink: sink}
+
+	// The filter sees each event as it is published, so none are buffered however many folders a mutation changes
+	_, a.cancel = d.Subscribe(uuid.Nil, func(e folder.Event) bool {
+		a.eventsMu.Lock()
+		defer a.eventsMu.Unlock()
+		if a.collecting {
+			a.events = append(a.events, e)
+		}
+		return false
+	})
+
+	return &Driver{auditor: a, actor: actor}
+}
+
+// As returns a driver over the same folders and sink acting for another actor
+func (d *Driver) As(actor string) *Driver {
+	return &Driver{auditor: d.auditor, actor: actor}
+}
+
+// Close stops recording the mutations of every actor
+func (d *Driver) Close() {
+	d.cancel()
+}
+
+// collect starts or stops collecting events, returning those collected since it started
+func (a *auditor) collect(start bool) []folder.Event {
+	a.eventsMu.Lock()
+	defer a.eventsMu.Unlock()
+	events := a.events
+	a.collecting, a.events = start, nil
+	return events
+}
+
+// record makes a mutation, writing a record of the folders it changed
+func (d *Driver) record(op Operation, mutate func() ([]folder.Folder, error)) ([]folder.Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.collect(true)
+	folders, err := mutate()
+	events := d.collect(false)
+	if err != nil || len(events) == 0 {
+		return folders, err
+	}
+
+	r := summarise(events)
+	r.Actor, r.Time, r.Operation = d.actor, time.Now(), op
+	if err := d.sink.Write(r); err != nil {
+		return folders, fmt.Errorf("auditing %s of %q: %w", op, r.OldPath+r.NewPath, err)
+	}
+	return folders, nil
+}
+
+// eventPaths returns where the folder of an event was and is left, each empty if it was created or deleted
+func eventPaths(e folder.Event) (string, string) {
+	switch e := e.(type) {
+	case folder.FolderCreated:
+		return "", e.Folder.Paths
+	case folder.FolderMoved:
+		return e.OldPath, e.NewPath
+	case folder.FolderRenamed:
+		return e.OldPath, e.NewPath
+	case folder.FolderDeleted:
+		return e.Folder.Paths, ""
+	}
+	return "", e.Path()
+}
+
+// summarise describes the events of a mutation by the folder it was made to,
+// which is the one nearest the root as the others are its descendants
+func summarise(events []folder.Event) Record {
+	var root folder.Event = events[0]
+	var depth int = -1
+	for _, e := range events {
+		oldPath, newPath := eventPaths(e)
+		if oldPath == "" {
+			oldPath = newPath
+		}
+		if d := strings.Count(oldPath, "."); depth < 0 || d < depth {
+			root, depth = e, d
+		}
+	}
+
+	oldPath, newPath := eventPaths(root)
+	return Record{OrgID: root.Org(), OldPath: oldPath, NewPath: newPath, Affected: len(events)}
+}
+
+func (d *Driver) GetFoldersByOrgID(orgID uuid.UUID) []folder.Folder {
+	return d.driver.GetFoldersByOrgID(orgID)
+}
+
+func (d *Driver) GetAllChildFolders(orgID uuid.UUID, name string) ([]folder.Folder, error) {
+	return d.driver.GetAllChildFolders(orgID, name)
+}
+
+func (d *Driver) MoveFolder(name string, dst string) ([]folder.Folder, error) {
+	return d.record(OpMove, func() ([]folder.Folder, error) {
+		return d.driver.MoveFolder(name, dst)
+	})
+}
+
+func (d *Driver) CreateFolder(orgID uuid.UUID, name string, parent string) ([]folder.Folder, error) {
+	return d.record(OpCreate, func() ([]folder.Folder, error) {
+		return d.driver.CreateFolder(orgID, name, parent)
+	})
+}
+
+func (d *Driver) MoveFolderByPath(orgID uuid.UUID, src string, dst string) ([]folder.Folder, error) {
+	return d.record(OpMove, func() ([]folder.Folder, error) {
+		return d.driver.MoveFolderByPath(orgID, src, dst)
+	})
+}
+
+func (d *Driver) RenameFolder(orgID uuid.UUID, path string, newName string) ([]folder.Folder, error) {
+	return d.record(OpRename, func() ([]folder.Folder, error) {
+		return d.driver.RenameFolder(orgID, path, newName)
+	})
+}
+
+func (d *Driver) DeleteFolder(orgID uuid.UUID, path string) ([]folder.Folder, error) {
+	return d.record(OpDelete, func() ([]folder.Folder, error) {
+		return d.driver.DeleteFolder(orgID, path)
+	})
+}
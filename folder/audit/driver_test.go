@@ -0,0 +1,138 @@
+package audit_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/folder/audit"
+	"github.com/georgechieng-sc/interns-2022/folder/drivertest"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingSink fails every write
+type failingSink struct{ audit.MemorySink }
+
+func (s *failingSink) Write(r audit.Record) error { return errors.New("disk full") }
+
+func Test_audit_Driver(t *testing.T) {
+	t.Parallel()
+
+	drivers := [...]struct {
+		testName  string
+		newDriver func([]folder.Folder) folder.IObservableDriver
+	}{
+		{testName: "Driver", newDriver: folder.NewDriver},
+		{testName: "Concurrent driver", newDriver: folder.NewConcurrentDriver},
+		{testName: "Sharded driver", newDriver: folder.NewShardedDriver},
+	}
+
+	for _, tt := range drivers {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			sink := audit.NewMemorySink()
+			d := audit.NewDriver(tt.newDriver(drivertest.MoveScenario()), sink, "ann")
+			defer d.Close()
+			before := time.Now()
+
+			_, err := d.MoveFolder("bravo", "golf")
+			require.NoError(t, err)
+			_, err = d.As("bob").RenameFolder(drivertest.OrgID, "golf", "hotel")
+			require.NoError(t, err)
+			_, err = d.CreateFolder(drivertest.OrgID, "india", "alpha.delta")
+			require.NoError(t, err)
+			_, err = d.MoveFolderByPath(drivertest.OrgID, "alpha.delta", "")
+			require.NoError(t, err)
+			_, err = d.DeleteFolder(drivertest.OtherOrgID, "foxtrot")
+			require.NoError(t, err)
+			_, err = d.DeleteFolder(drivertest.OrgID, "missing")
+			assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+			_, err = d.MoveFolderByPath(drivertest.OrgID, "hotel", "")
+			require.NoError(t, err)
+
+			records, err := sink.Query(drivertest.OrgID, time.Time{}, time.Time{})
+			require.NoError(t, err)
+			for i := range records {
+				assert.WithinRange(t, records[i].Time, before, time.Now())
+				records[i].Time = time.Time{}
+			}
+			assert.Equal(t, []audit.Record{
+				{Actor: "ann", OrgID: drivertest.OrgID, Operation: audit.OpMove, OldPath: "alpha.bravo", NewPath: "golf.bravo", Affected: 2},
+				{Actor: "bob", OrgID: drivertest.OrgID, Operation: audit.OpRename, OldPath: "golf", NewPath: "hotel", Affected: 3},
+				{Actor: "ann", OrgID: drivertest.OrgID, Operation: audit.OpCreate, NewPath: "alpha.delta.india", Affected: 1},
+				{Actor: "ann", OrgID: drivertest.OrgID, Operation: audit.OpMove, OldPath: "alpha.delta", NewPath: "delta", Affected: 3},
+			}, records, "Failed mutations and those changing nothing are not recorded")
+
+			records, err = sink.Query(drivertest.OtherOrgID, time.Time{}, time.Time{})
+			require.NoError(t, err)
+			require.Len(t, records, 1)
+			assert.Equal(t, audit.OpDelete, records[0].Operation)
+			assert.Equal(t, "foxtrot", records[0].OldPath)
+			assert.Empty(t, records[0].NewPath)
+		})
+	}
+}
+
+func Test_audit_Driver_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	sink := audit.NewMemorySink()
+	d := audit.NewDriver(folder.NewShardedDriver([]folder.Folder{}), sink, "ann")
+	defer d.Close()
+
+	orgs := make([]uuid.UUID, 8)
+	var wg sync.WaitGroup
+	for i := range orgs {
+		orgs[i] = uuid.Must(uuid.NewV4())
+		wg.Add(1)
+		go func(d *audit.Driver, orgID uuid.UUID) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_, err := d.CreateFolder(orgID, "a", "")
+				assert.NoError(t, err)
+				_, err = d.DeleteFolder(orgID, "a")
+				assert.NoError(t, err)
+			}
+		}(d.As(orgs[i].String()), orgs[i])
+	}
+	wg.Wait()
+
+	for _, orgID := range orgs {
+		records, err := sink.Query(orgID, time.Time{}, time.Time{})
+		require.NoError(t, err)
+		require.Len(t, records, 100)
+		for _, r := range records {
+			assert.Equal(t, orgID.String(), r.Actor)
+			assert.Equal(t, 1, r.Affected, "Each record counts only the folders of its own mutation")
+		}
+	}
+}
+
+func Test_audit_Driver_FailedWrite(t *testing.T) {
+	t.Parallel()
+
+	sampleFolders := drivertest.MoveScenario()
+
+	d := audit.NewDriver(drivertest.NewMoveDriver(), &failingSink{}, "ann")
+	defer d.Close()
+
+	folders, err := d.DeleteFolder(drivertest.OrgID, "golf")
+	assert.ErrorContains(t, err, "disk full")
+	assert.NotContains(t, folders, sampleFolders[6], "The mutation is still made")
+	assert.NotContains(t, d.GetFoldersByOrgID(drivertest.OrgID), sampleFolders[6])
+}
+
+func Test_audit_Conformance(t *testing.T) {
+	t.Parallel()
+
+	drivertest.RunConformance(t, func(folders []folder.Folder) folder.IDriver {
+		d := audit.NewDriver(folder.NewDriver(folders), audit.NewMemorySink(), "ann")
+		t.Cleanup(d.Close)
+		return d
+	})
+}
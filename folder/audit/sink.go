@@ -0,0 +1,193 @@
+// Package audit records who changed which folders, and when.
+//
+// A Driver writes a Record for each mutation made through it to a Sink, which can be queried
+// for an organisation's records over a range of time. MemorySink keeps records for tests,
+// while FileSink appends them to a file as JSON lines.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Operation is the kind of mutation a Record describes
+type Operation string
+
+const (
+	OpCreate Operation = "create"
+	OpMove   Operation = "move"
+	OpRename Operation = "rename"
+	OpDelete Operation = "delete"
+)
+
+// Record describes one mutation, by the folder it was made to.
+// Descendants moved, renamed or deleted along with that folder are counted in Affected.
+type Record struct {
+	Actor     string    `json:"actor"`
+	Time      time.Time `json:"time"`
+	OrgID     uuid.UUID `json:"org_id"`
+	Operation Operation `json:"operation"`
+
+	// OldPath is where the folder was, empty for a create.
+	OldPath string `json:"old_path,omitempty"`
+
+	// NewPath is where the folder is left, empty for a delete.
+	NewPath string `json:"new_path,omitempty"`
+
+	Affected int `json:"affected"`
+}
+
+// Sink keeps records, which must be safe for concurrent use
+type Sink interface {
+	// Write keeps a record.
+	Write(r Record) error
+
+	// Query returns the records of an organisation made from from, until before to, in the order written.
+	// A zero from or to leaves that end of the range open.
+	Query(orgID uuid.UUID, from time.Time, to time.Time) ([]Record, error)
+}
+
+// matches checks if a record belongs to an organisation and range of time, as given to Query
+func matches(r Record, orgID uuid.UUID, from time.Time, to time.Time) bool {
+	return r.OrgID == orgID && (from.IsZero() || !r.Time.Before(from)) && (to.IsZero() || r.Time.Before(to))
+}
+
+// MemorySink keeps records in memory
+type MemorySink struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// NewMemorySink creates an empty sink
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+func (s *MemorySink) Write(r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, r)
+	return nil
+}
+
+func (s *MemorySink) Query(orgID uuid.UUID, from time.Time, to time.Time) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := []Record{}
+	for _, r := range s.records {
+		if matches(r, orgID, from, to) {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+// FileSink appends records to a file, one JSON object per line.
+// Querying reads the whole file, so suits the occasional compliance request rather than frequent reads.
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// OpenFile opens the sink kept at path, creating it if need be.
+// A torn final line, left by a crash mid-write, is truncated so later records start on a line of their own.
+func OpenFile(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := completeLines(file)
+	if err == nil {
+		err = file.Truncate(end)
+	}
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("truncate torn record: %w", err)
+	}
+	return &FileSink{path: path, file: file}, nil
+}
+
+// completeLines returns the size of a file up to the end of its last complete line
+func completeLines(r io.Reader) (int64, error) {
+	var end, offset int64 = 0, 0
+	reader := bufio.NewReader(r)
+	for {
+		text, err := reader.ReadBytes('\n')
+		offset += int64(len(text))
+		if err == io.EOF {
+			return end, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		end = offset
+	}
+}
+
+// Write appends a record, syncing it to disk before returning
+func (s *FileSink) Write(r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+func (s *FileSink) Query(orgID uuid.UUID, from time.Time, to time.Time) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readRecords(file, orgID, from, to)
+}
+
+// readRecords reads JSON lines of records, keeping those matching an organisation and range of time.
+// An unterminated final line is a record torn by a crash mid-write, so is skipped.
+func readRecords(r io.Reader, orgID uuid.UUID, from time.Time, to time.Time) ([]Record, error) {
+	records := []Record{}
+	reader := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		text, err := reader.ReadBytes('\n')
+		if err == nil {
+			var record Record
+			if err := json.Unmarshal(text, &record); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			if matches(record, orgID, from, to) {
+				records = append(records, record)
+			}
+		}
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Close closes the file
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
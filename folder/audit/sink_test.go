@@ -0,0 +1,129 @@
+package audit_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/georgechieng-sc/interns-2022/folder/audit"
+	"github.com/georgechieng-sc/interns-2022/folder/drivertest"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+var sampleRecords = []audit.Record{
+	{Actor: "ann", Time: start, OrgID: drivertest.OrgID, Operation: audit.OpCreate, NewPath: "alpha", Affected: 1},
+	{Actor: "bob", Time: start.Add(time.Hour), OrgID: drivertest.OtherOrgID, Operation: audit.OpDelete, OldPath: "foxtrot", Affected: 3},
+	{Actor: "ann", Time: start.Add(2 * time.Hour), OrgID: drivertest.OrgID, Operation: audit.OpMove, OldPath: "alpha.bravo", NewPath: "golf.bravo", Affected: 2},
+	{Actor: "bob", Time: start.Add(3 * time.Hour), OrgID: drivertest.OrgID, Operation: audit.OpRename, OldPath: "golf", NewPath: "hotel", Affected: 3},
+}
+
+func Test_audit_Sink_Query(t *testing.T) {
+	t.Parallel()
+
+	sinks := [...]struct {
+		testName string
+		newSink  func(t *testing.T) audit.Sink
+	}{
+		{testName: "Memory", newSink: func(t *testing.T) audit.Sink { return audit.NewMemorySink() }},
+		{testName: "File", newSink: func(t *testing.T) audit.Sink {
+			s, err := audit.OpenFile(filepath.Join(t.TempDir(), "audit.jsonl"))
+			require.NoError(t, err)
+			t.Cleanup(func() { s.Close() })
+			return s
+		}},
+	}
+
+	tests := [...]struct {
+		testName string
+		orgID    uuid.UUID
+		from     time.Time
+		to       time.Time
+		want     []audit.Record
+	}{
+		{testName: "Every record of the org", orgID: drivertest.OrgID, want: []audit.Record{sampleRecords[0], sampleRecords[2], sampleRecords[3]}},
+		{testName: "Only records of the org", orgID: drivertest.OtherOrgID, want: sampleRecords[1:2]},
+		{testName: "From is inclusive", orgID: drivertest.OrgID, from: start.Add(2 * time.Hour), want: sampleRecords[2:4]},
+		{testName: "To is exclusive", orgID: drivertest.OrgID, to: start.Add(2 * time.Hour), want: sampleRecords[0:1]},
+		{testName: "Within a range", orgID: drivertest.OrgID, from: start.Add(time.Minute), to: start.Add(3 * time.Hour), want: sampleRecords[2:3]},
+		{testName: "Unknown org", orgID: uuid.FromStringOrNil("38b9879b-f73b-4b0e-b9d9-4fc4c23643a7"), want: []audit.Record{}},
+	}
+
+	for _, sink := range sinks {
+		t.Run(sink.testName, func(t *testing.T) {
+			t.Parallel()
+
+			s := sink.newSink(t)
+			for _, r := range sampleRecords {
+				require.NoError(t, s.Write(r))
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.testName, func(t *testing.T) {
+					records, err := s.Query(tt.orgID, tt.from, tt.to)
+					require.NoError(t, err)
+					assert.Equal(t, tt.want, records)
+				})
+			}
+		})
+	}
+}
+
+func Test_audit_FileSink_Reopen(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	s, err := audit.OpenFile(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Write(sampleRecords[0]))
+	require.NoError(t, s.Close())
+
+	s, err = audit.OpenFile(path)
+	require.NoError(t, err)
+	defer s.Close()
+	require.NoError(t, s.Write(sampleRecords[2]))
+
+	records, err := s.Query(drivertest.OrgID, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []audit.Record{sampleRecords[0], sampleRecords[2]}, records, "Records are appended to those already kept")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `{"actor":"ann","time":"2024-03-01T09:00:00Z","org_id":"c59cc5c1-9b81-4d00-95e3-22c6efdaf134","operation":"create","new_path":"alpha","affected":1}`+"\n")
+
+	require.NoError(t, os.WriteFile(path, append(data, "not json\n"...), 0o644))
+	_, err = s.Query(drivertest.OrgID, time.Time{}, time.Time{})
+	assert.ErrorContains(t, err, "line 3")
+}
+
+func Test_audit_FileSink_TornFinalLine(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	s, err := audit.OpenFile(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Write(sampleRecords[0]))
+
+	// A crash mid-write leaves part of a record without its newline
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, append(append([]byte{}, data...), `{"actor":"ann","ti`...), 0o644))
+
+	records, err := s.Query(drivertest.OrgID, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []audit.Record{sampleRecords[0]}, records, "Torn record is skipped")
+	require.NoError(t, s.Close())
+
+	s, err = audit.OpenFile(path)
+	require.NoError(t, err)
+	defer s.Close()
+	require.NoError(t, s.Write(sampleRecords[2]))
+
+	records, err = s.Query(drivertest.OrgID, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []audit.Record{sampleRecords[0], sampleRecords[2]}, records, "Later records are not joined to the torn one")
+}
@@ -0,0 +1,166 @@
+package folder_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/folder/workload"
+	"github.com/gofrs/uuid"
+)
+
+// Run with `go test ./folder -run '^$' -bench . -count 5 > new.txt`, then compare against an earlier run
+// with `go run main.go benchcmp old.txt new.txt`. -short skips the million folder trees.
+
+// benchSizes are the numbers of folders in the trees benchmarked
+var benchSizes = [...]int{1_000, 10_000, 100_000, 1_000_000}
+
+// benchShapes are the kinds of trees benchmarked. Deep trees stop short of a million folders,
+// whose paths alone would take gigabytes.
+var benchShapes = [...]struct {
+	name    string
+	deep    float64
+	wide    float64
+	maxSize int
+}{
+	{name: "bushy", maxSize: 1_000_000},
+	{name: "deep", deep: 1, maxSize: 100_000},
+	{name: "wide", wide: 1, maxSize: 1_000_000},
+}
+
+// benchTree is a generated tree along with folders to operate on, renamed so their names are unique
+type benchTree struct {
+	folders []folder.Folder
+	orgID   uuid.UUID
+
+	// parent is the root with the most descendants, dsts two other roots,
+	// and src a child of a root other than those
+	parent string
+	src    string
+	dsts   [2]string
+}
+
+// runBenchTrees runs bench against a tree of each shape and size
+func runBenchTrees(b *testing.B, bench func(b *testing.B, tree *benchTree)) {
+	for _, shape := range benchShapes {
+		for _, size := range benchSizes {
+			if size > shape.maxSize || (testing.Short() && size >= 1_000_000) {
+				continue
+			}
+
+			b.Run(fmt.Sprintf("%s/%d", shape.name, size), func(b *testing.B) {
+				tree := newBenchTree(b, size, shape.deep, shape.wide)
+				b.ReportAllocs()
+				b.ResetTimer()
+				bench(b, tree)
+			})
+		}
+	}
+}
+
+func newBenchTree(b *testing.B, size int, deep float64, wide float64) *benchTree {
+	b.Helper()
+
+	var buf bytes.Buffer
+	_, err := workload.Generate(&buf, workload.NDJSON, workload.Config{
+		Seed:     int64(size),
+		Folders:  size,
+		Orgs:     10,
+		Skew:     1,
+		MaxTree:  max(50, size/50),
+		DeepRate: deep,
+		WideRate: wide,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	folders, err := folder.ReadNDJSON(&buf)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	orgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+	roots := []string{}
+	descendants := map[string]int{}
+	children := map[string]string{}
+	for _, f := range folders {
+		if f.OrgId != orgID {
+			continue
+		}
+
+		root, rest, _ := strings.Cut(f.Paths, ".")
+		if rest == "" {
+			roots = append(roots, root)
+		} else if !strings.Contains(rest, ".") {
+			children[root] = f.Paths
+		}
+		descendants[root]++
+	}
+	if len(roots) < 3 {
+		b.Fatalf("%d trees among %d folders, but 3 are needed", len(roots), size)
+	}
+
+	var parent string = roots[0]
+	for _, root := range roots {
+		if descendants[root] > descendants[parent] {
+			parent = root
+		}
+	}
+
+	tree := &benchTree{orgID: orgID, parent: "bench-parent", src: "bench-src", dsts: [2]string{"bench-dst-0", "bench-dst-1"}}
+	renames := [][2]string{{parent, tree.parent}}
+	for _, root := range roots {
+		switch {
+		case root == parent:
+		case len(renames) < 3:
+			renames = append(renames, [2]string{root, tree.dsts[len(renames)-1]})
+		case children[root] != "" && len(renames) == 3:
+			renames = append(renames, [2]string{children[root], tree.src})
+		}
+	}
+	if len(renames) < 4 {
+		b.Fatalf("no folder to move among %d folders", size)
+	}
+
+	d := folder.NewDriver(folders)
+	for _, rename := range renames {
+		if folders, err = d.RenameFolder(orgID, rename[0], rename[1]); err != nil {
+			b.Fatal(err)
+		}
+	}
+	tree.folders = folders
+	return tree
+}
+
+func Benchmark_folder_GetFoldersByOrgID(b *testing.B) {
+	runBenchTrees(b, func(b *testing.B, tree *benchTree) {
+		d := folder.NewDriver(tree.folders)
+		for i := 0; i < b.N; i++ {
+			d.GetFoldersByOrgID(tree.orgID)
+		}
+	})
+}
+
+func Benchmark_folder_GetAllChildFolders(b *testing.B) {
+	runBenchTrees(b, func(b *testing.B, tree *benchTree) {
+		d := folder.NewDriver(tree.folders)
+		for i := 0; i < b.N; i++ {
+			if _, err := d.GetAllChildFolders(tree.orgID, tree.parent); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func Benchmark_folder_MoveFolder(b *testing.B) {
+	runBenchTrees(b, func(b *testing.B, tree *benchTree) {
+		d := folder.NewDriver(tree.folders)
+		for i := 0; i < b.N; i++ {
+			if _, err := d.MoveFolder(tree.src, tree.dsts[i%2]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package folder
+
+import (
+	"sync"
+
+	"github.com/gofrs/uuid"
+)
+
+// A driver which may be shared between goroutines.
+// Reads hold a read lock so run in parallel, while mutations hold the write lock so run one at a time.
+// The underlying driver never modifies a slice once returned, as mutations build new ones,
+// so results stay valid after the lock is released.
+type concurrentDriver struct {
+	mu     sync.RWMutex
+	driver driver
+}
+
+// NewConcurrentDriver creates a driver which is safe for concurrent use
+func NewConcurrentDriver(folders []Folder) IObservableDriver {
+	return &concurrentDriver{
+		driver: driver{folders: folders, events: &Publisher{}},
+	}
+}
+
+func (c *concurrentDriver) setLimits(limits Limits) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.driver.setLimits(limits)
+}
+
+func (c *concurrentDriver) getLimits() Limits {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.driver.getLimits()
+}
+
+func (c *concurrentDriver) GetFoldersByOrgID(orgID uuid.UUID) []Folder {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.driver.GetFoldersByOrgID(orgID)
+}
+
+func (c *concurrentDriver) GetAllChildFolders(orgID uuid.UUID, name string) ([]Folder, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.driver.GetAllChildFolders(orgID, name)
+}
+
+func (c *concurrentDriver) MoveFolder(name string, dst string) ([]Folder, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.driver.MoveFolder(name, dst)
+}
+
+func (c *concurrentDriver) CreateFolder(orgID uuid.UUID, name string, parent string) ([]Folder, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.driver.CreateFolder(orgID, name, parent)
+}
+
+func (c *concurrentDriver) MoveFolderByPath(orgID uuid.UUID, src string, dst string) ([]Folder, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.driver.MoveFolderByPath(orgID, src, dst)
+}
+
+func (c *concurrentDriver) RenameFolder(orgID uuid.UUID, path string, newName string) ([]Folder, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.driver.RenameFolder(orgID, path, newName)
+}
+
+func (c *concurrentDriver) DeleteFolder(orgID uuid.UUID, path string) ([]Folder, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.driver.DeleteFolder(orgID, path)
+}
+
+func (c *concurrentDriver) Version(orgID uuid.UUID) uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.driver.Version(orgID)
+}
+
+func (c *concurrentDriver) GetVersionedFoldersByOrgID(orgID uuid.UUID) ([]Folder, uint64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.driver.GetVersionedFoldersByOrgID(orgID)
+}
+
+func (c *concurrentDriver) GetVersionedChildFolders(orgID uuid.UUID, name string) ([]Folder, uint64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.driver.GetVersionedChildFolders(orgID, name)
+}
+
+func (c *concurrentDriver) MoveFolderIfVersion(version uint64, name string, dst string) ([]Folder, uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.driver.MoveFolderIfVersion(version, name, dst)
+}
+
+func (c *concurrentDriver) CreateFolderIfVersion(orgID uuid.UUID, version uint64, name string, parent string) ([]Folder, uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.driver.CreateFolderIfVersion(orgID, version, name, parent)
+}
+
+func (c *concurrentDriver) MoveFolderByPathIfVersion(orgID uuid.UUID, version uint64, src string, dst string) ([]Folder, uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.driver.MoveFolderByPathIfVersion(orgID, version, src, dst)
+}
+
+func (c *concurrentDriver) RenameFolderIfVersion(orgID uuid.UUID, version uint64, path string, newName string) ([]Folder, uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.driver.RenameFolderIfVersion(orgID, version, path, newName)
+}
+
+func (c *concurrentDriver) DeleteFolderIfVersion(orgID uuid.UUID, version uint64, path string) ([]Folder, uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.driver.DeleteFolderIfVersion(orgID, version, path)
+}
+
+// Subscribe needs no lock, as events are published while the mutation making them holds the write lock
+func (c *concurrentDriver) Subscribe(orgID uuid.UUID, filter func(Event) bool) (<-chan Event, func()) {
+	return c.driver.Subscribe(orgID, filter)
+}
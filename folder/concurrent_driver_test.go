@@ -0,0 +1,138 @@
+package folder_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_folder_ConcurrentDriver(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+
+	d := folder.NewConcurrentDriver([]folder.Folder{
+		{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+		{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+		{Name: "charlie", OrgId: validOrgId, Paths: "charlie"},
+	})
+
+	_, err := d.CreateFolder(validOrgId, "delta", "alpha.bravo")
+	require.NoError(t, err)
+
+	_, err = d.MoveFolderByPath(validOrgId, "alpha.bravo", "charlie")
+	require.NoError(t, err)
+
+	children, err := d.GetAllChildFolders(validOrgId, "charlie")
+	require.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "bravo", OrgId: validOrgId, Paths: "charlie.bravo"},
+		{Name: "delta", OrgId: validOrgId, Paths: "charlie.bravo.delta"},
+	}, children)
+
+	_, err = d.MoveFolderByPath(validOrgId, "charlie", "charlie.bravo")
+	assert.ErrorIs(t, err, folder.ErrMoveToChild, "Errors from the underlying driver are passed on")
+}
+
+// Test_folder_ConcurrentDriver_Stress mutates a driver from many goroutines while others read it.
+// Run with -race to check for data races; the checks below catch readers seeing a half applied mutation.
+func Test_folder_ConcurrentDriver_Stress(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+	const movers, creators, readers, iterations = 4, 4, 8, 200
+
+	// Two parents, each with one child per mover, which the movers swap between the parents
+	folders := []folder.Folder{
+		{Name: "left", OrgId: validOrgId, Paths: "left"},
+		{Name: "right", OrgId: validOrgId, Paths: "right"},
+		{Name: "other", OrgId: otherOrgId, Paths: "other"},
+	}
+	for i := 0; i < movers; i++ {
+		var name string = fmt.Sprintf("m%d", i)
+		folders = append(folders,
+			folder.Folder{Name: name, OrgId: validOrgId, Paths: "left." + name},
+			folder.Folder{Name: "leaf", OrgId: validOrgId, Paths: "left." + name + ".leaf"},
+		)
+	}
+	d := folder.NewConcurrentDriver(folders)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, movers+creators+readers)
+
+	for i := 0; i < movers; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			var from, to string = "left", "right"
+			for j := 0; j < iterations; j++ {
+				if _, err := d.MoveFolderByPath(validOrgId, from+"."+name, to); err != nil {
+					errs <- fmt.Errorf("move %s from %s to %s: %w", name, from, to, err)
+					return
+				}
+				from, to = to, from
+			}
+		}(fmt.Sprintf("m%d", i))
+	}
+
+	for i := 0; i < creators; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				if _, err := d.CreateFolder(otherOrgId, name, "other"); err != nil {
+					errs <- fmt.Errorf("create %s: %w", name, err)
+					return
+				}
+				if _, err := d.DeleteFolder(otherOrgId, "other."+name); err != nil {
+					errs <- fmt.Errorf("delete %s: %w", name, err)
+					return
+				}
+			}
+		}(fmt.Sprintf("c%d", i))
+	}
+
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				// The movers only rearrange folders, so every snapshot has all of them, well formed
+				snapshot := d.GetFoldersByOrgID(validOrgId)
+				if len(snapshot) != 2+2*movers {
+					errs <- fmt.Errorf("read %d folders, want %d", len(snapshot), 2+2*movers)
+					return
+				}
+				if problems := folder.Validate(snapshot); len(problems) > 0 {
+					errs <- errors.Join(problems...)
+					return
+				}
+
+				if _, err := d.GetAllChildFolders(validOrgId, "leaf"); err != nil {
+					errs <- fmt.Errorf("children of leaf: %w", err)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	// Each mover made an even number of moves, so is back beneath left
+	children, err := d.GetAllChildFolders(validOrgId, "left")
+	require.NoError(t, err)
+	assert.Len(t, children, 2*movers)
+	assert.Equal(t, []folder.Folder{folders[2]}, d.GetFoldersByOrgID(otherOrgId))
+}
@@ -0,0 +1,22 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/folder/drivertest"
+)
+
+func Test_folder_Conformance(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range propertyDrivers {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			drivertest.RunConformance(t, func(folders []folder.Folder) folder.IDriver {
+				return tt.newDriver(folders)
+			})
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package folder
+
+import "github.com/gofrs/uuid"
+
+func (f *driver) CreateFolder(orgID uuid.UUID, name string, parent string) ([]Folder, error) {
+	if !isValidName(name) {
+		return []Folder{}, ErrInvalidName
+	}
+
+	if parent != "" {
+		if _, found := findFolderByPath(&f.folders, orgID, parent); !found {
+			return []Folder{}, ErrFolderNotFound
+		}
+	}
+
+	var newFolder Folder = Folder{
+		Name:  name,
+		OrgId: orgID,
+		Paths: JoinPath(parent, name),
+	}
+
+	if _, exists := findFolderByPath(&f.folders, orgID, newFolder.Paths); exists {
+		return []Folder{}, ErrFolderExists
+	}
+
+	if err := f.checkCreate(orgID, newFolder.Paths); err != nil {
+		return []Folder{}, err
+	}
+
+	resultAfterCreate := make([]Folder, 0, len(f.folders)+1)
+	resultAfterCreate = append(resultAfterCreate, f.folders...)
+	resultAfterCreate = append(resultAfterCreate, newFolder)
+
+	f.folders = resultAfterCreate
+	f.bump(orgID)
+	f.publishCreated(newFolder)
+	return resultAfterCreate, nil
+}
@@ -0,0 +1,114 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_CreateFolder(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+	tests := [...]struct {
+		testName string
+		name     string
+		parent   string
+		folders  []folder.Folder
+		want     []folder.Folder
+		err      error
+	}{
+		{
+			testName: "Create root folder",
+			name:     "a",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+			},
+			want: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+			},
+		},
+		{
+			testName: "Create nested folder",
+			name:     "c2",
+			parent:   "fold.c1",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "c1", OrgId: validOrgId, Paths: "fold.c1"},
+			},
+			want: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "c1", OrgId: validOrgId, Paths: "fold.c1"},
+				{Name: "c2", OrgId: validOrgId, Paths: "fold.c1.c2"},
+			},
+		},
+		{
+			testName: "Same path in another organisation does not conflict",
+			name:     "fold",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: otherOrgId, Paths: "fold"},
+			},
+			want: []folder.Folder{
+				{Name: "fold", OrgId: otherOrgId, Paths: "fold"},
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+			},
+		},
+		{
+			testName: "Error: Folder already exists",
+			name:     "c1",
+			parent:   "fold",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "c1", OrgId: validOrgId, Paths: "fold.c1"},
+			},
+			err: folder.ErrFolderExists,
+		},
+		{
+			testName: "Error: Parent does not exist",
+			name:     "c1",
+			parent:   "missing",
+			folders:  []folder.Folder{},
+			err:      folder.ErrFolderNotFound,
+		},
+		{
+			testName: "Error: Parent belongs to another organisation",
+			name:     "c1",
+			parent:   "fold",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: otherOrgId, Paths: "fold"},
+			},
+			err: folder.ErrFolderNotFound,
+		},
+		{
+			testName: "Error: Name contains a dot",
+			name:     "a.b",
+			folders:  []folder.Folder{},
+			err:      folder.ErrInvalidName,
+		},
+		{
+			testName: "Error: Name is empty",
+			name:     "",
+			folders:  []folder.Folder{},
+			err:      folder.ErrInvalidName,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			f := folder.NewDriver(tt.folders)
+			folders, err := f.CreateFolder(validOrgId, tt.name, tt.parent)
+
+			if tt.err == nil {
+				assert.NoError(t, err, tt.testName)
+				assert.Equal(t, tt.want, folders, tt.testName)
+				assert.ElementsMatch(t, tt.want, append(f.GetFoldersByOrgID(validOrgId), f.GetFoldersByOrgID(otherOrgId)...), "Driver keeps the result")
+			} else {
+				assert.ErrorIs(t, err, tt.err, tt.testName)
+			}
+		})
+	}
+}
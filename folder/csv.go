@@ -0,0 +1,145 @@
+package folder
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// csvColumns are the CSV columns, in the order they are written
+var csvColumns = [...]string{"name", "org_id", "paths"}
+
+// CSVOptions configures how folders are read from and written to CSV
+type CSVOptions struct {
+	// Comma is the field delimiter, ',' if unset
+	Comma rune
+}
+
+// ParseError reports a malformed row or node in imported folders, along with its line,
+// or a folder which cannot be exported, along with its position
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ReadCSV reads folders from CSV with the columns name, org_id and paths.
+// A header row naming the columns is optional, and may list them in any order.
+func ReadCSV(r io.Reader, opts CSVOptions) ([]Folder, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+
+	columns := map[string]int{"name": 0, "org_id": 1, "paths": 2}
+	folders := []Folder{}
+	lines := []int{}
+	seenPaths := map[uuid.UUID]map[string]bool{}
+
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, &ParseError{Line: parseErr.Line, Err: parseErr.Err}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(record) != len(csvColumns) {
+			return nil, &ParseError{Line: line, Err: fmt.Errorf("expected %d columns, found %d", len(csvColumns), len(record))}
+		}
+
+		if header, ok := csvHeader(record); first && ok {
+			columns = header
+			continue
+		}
+
+		orgId, err := uuid.FromString(strings.TrimSpace(record[columns["org_id"]]))
+		if err != nil {
+			return nil, &ParseError{Line: line, Err: fmt.Errorf("invalid org_id: %w", err)}
+		}
+
+		var f Folder = Folder{
+			Name:  strings.TrimSpace(record[columns["name"]]),
+			OrgId: orgId,
+			Paths: strings.TrimSpace(record[columns["paths"]]),
+		}
+		if err := checkFolder(&f); err != nil {
+			return nil, &ParseError{Line: line, Err: fmt.Errorf("%w: %q", err, f.Paths)}
+		}
+
+		if seenPaths[orgId] == nil {
+			seenPaths[orgId] = map[string]bool{}
+		}
+		if seenPaths[orgId][f.Paths] {
+			return nil, &ParseError{Line: line, Err: fmt.Errorf("%w: %q", ErrFolderExists, f.Paths)}
+		}
+		seenPaths[orgId][f.Paths] = true
+
+		folders = append(folders, f)
+		lines = append(lines, line)
+	}
+
+	// Rows may come in any order, so parents are only checked once all are read
+	for i, f := range folders {
+		if parent := ParentPath(f.Paths); parent != "" && !seenPaths[f.OrgId][parent] {
+			return nil, &ParseError{Line: lines[i], Err: fmt.Errorf("%w: parent of %q", ErrFolderNotFound, f.Paths)}
+		}
+	}
+
+	return folders, nil
+}
+
+// WriteCSV writes folders as CSV, preceded by a header row
+func WriteCSV(w io.Writer, folders []Folder, opts CSVOptions) error {
+	writer := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		writer.Comma = opts.Comma
+	}
+
+	if err := writer.Write(csvColumns[:]); err != nil {
+		return err
+	}
+
+	for _, f := range folders {
+		if err := writer.Write([]string{f.Name, f.OrgId.String(), f.Paths}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvHeader checks if a record names each column once, returning the index of each
+func csvHeader(record []string) (map[string]int, bool) {
+	columns := map[string]int{}
+	for i, field := range record {
+		columns[strings.ToLower(strings.TrimSpace(field))] = i
+	}
+
+	for _, c := range csvColumns {
+		if _, ok := columns[c]; !ok {
+			return nil, false
+		}
+	}
+	return columns, true
+}
@@ -0,0 +1,129 @@
+package folder_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_ReadCSV(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+
+	tests := [...]struct {
+		testName string
+		input    string
+		opts     folder.CSVOptions
+		want     []folder.Folder
+		errLine  int
+	}{
+		{
+			testName: "Without header",
+			input: "alpha,c59cc5c1-9b81-4d00-95e3-22c6efdaf134,alpha\n" +
+				"bravo,c59cc5c1-9b81-4d00-95e3-22c6efdaf134,alpha.bravo\n",
+			want: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+			},
+		},
+		{
+			testName: "Header with reordered columns",
+			input: "Paths, Name, ORG_ID\n" +
+				"alpha.bravo,bravo,c59cc5c1-9b81-4d00-95e3-22c6efdaf134\n" +
+				"alpha,alpha,c59cc5c1-9b81-4d00-95e3-22c6efdaf134\n",
+			want: []folder.Folder{
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+			},
+		},
+		{
+			testName: "Configured delimiter",
+			input:    "name;org_id;paths\nalpha;c59cc5c1-9b81-4d00-95e3-22c6efdaf134;alpha\n",
+			opts:     folder.CSVOptions{Comma: ';'},
+			want: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+			},
+		},
+		{
+			testName: "Empty input",
+			input:    "",
+			want:     []folder.Folder{},
+		},
+		{
+			testName: "Error: Wrong number of columns",
+			input:    "name,org_id,paths\nalpha,c59cc5c1-9b81-4d00-95e3-22c6efdaf134\n",
+			errLine:  2,
+		},
+		{
+			testName: "Error: Invalid org_id",
+			input:    "alpha,c59cc5c1-9b81-4d00-95e3-22c6efdaf134,alpha\nbravo,not-a-uuid,alpha.bravo\n",
+			errLine:  2,
+		},
+		{
+			testName: "Error: Name is not the last label",
+			input:    "name,org_id,paths\n\nalpha,c59cc5c1-9b81-4d00-95e3-22c6efdaf134,alpha\nbravo,c59cc5c1-9b81-4d00-95e3-22c6efdaf134,alpha.charlie\n",
+			errLine:  4,
+		},
+		{
+			testName: "Error: Empty label",
+			input:    "alpha,c59cc5c1-9b81-4d00-95e3-22c6efdaf134,alpha\nbravo,c59cc5c1-9b81-4d00-95e3-22c6efdaf134,alpha..bravo\n",
+			errLine:  2,
+		},
+		{
+			testName: "Error: Duplicate path",
+			input:    "alpha,c59cc5c1-9b81-4d00-95e3-22c6efdaf134,alpha\nalpha,c59cc5c1-9b81-4d00-95e3-22c6efdaf134,alpha\n",
+			errLine:  2,
+		},
+		{
+			testName: "Error: Missing parent",
+			input:    "alpha,c59cc5c1-9b81-4d00-95e3-22c6efdaf134,alpha\ncharlie,c59cc5c1-9b81-4d00-95e3-22c6efdaf134,alpha.bravo.charlie\n",
+			errLine:  2,
+		},
+		{
+			testName: "Error: Unterminated quote",
+			input:    "alpha,c59cc5c1-9b81-4d00-95e3-22c6efdaf134,alpha\n\"bravo,c59cc5c1-9b81-4d00-95e3-22c6efdaf134,alpha.bravo\n",
+			errLine:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			folders, err := folder.ReadCSV(strings.NewReader(tt.input), tt.opts)
+
+			if tt.errLine == 0 {
+				assert.NoError(t, err, tt.testName)
+				assert.Equal(t, tt.want, folders, tt.testName)
+			} else {
+				var parseErr *folder.ParseError
+				if assert.True(t, errors.As(err, &parseErr), tt.testName) {
+					assert.Equal(t, tt.errLine, parseErr.Line, tt.testName)
+				}
+			}
+		})
+	}
+}
+
+func Test_folder_WriteCSV(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	folders := []folder.Folder{
+		{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+		{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, folder.WriteCSV(&buf, folders, folder.CSVOptions{Comma: '\t'}))
+	assert.Equal(t, "name\torg_id\tpaths\n"+
+		"alpha\tc59cc5c1-9b81-4d00-95e3-22c6efdaf134\talpha\n"+
+		"bravo\tc59cc5c1-9b81-4d00-95e3-22c6efdaf134\talpha.bravo\n", buf.String())
+
+	read, err := folder.ReadCSV(&buf, folder.CSVOptions{Comma: '\t'})
+	assert.NoError(t, err)
+	assert.Equal(t, folders, read, "Round trips")
+}
@@ -0,0 +1,24 @@
+package folder
+
+import "github.com/gofrs/uuid"
+
+func (f *driver) DeleteFolder(orgID uuid.UUID, path string) ([]Folder, error) {
+	folder, found := findFolderByPath(&f.folders, orgID, path)
+	if !found {
+		return []Folder{}, ErrFolderNotFound
+	}
+
+	var before []Folder = f.folders
+	f.folders = filterFolders(&f.folders, func(f Folder) bool {
+		return !isDeletedFolder(&f, &folder)
+	})
+	f.bump(orgID)
+	f.publishDeleted(before, f.folders)
+	return f.folders, nil
+}
+
+// isDeletedFolder checks if the folder is removed along with deleted
+func isDeletedFolder(folder *Folder, deleted *Folder) bool {
+	return folder.OrgId == deleted.OrgId &&
+		(folder.Paths == deleted.Paths || IsDescendantPath(deleted.Paths, folder.Paths))
+}
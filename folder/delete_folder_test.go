@@ -0,0 +1,71 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_DeleteFolder(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+	tests := [...]struct {
+		testName string
+		path     string
+		folders  []folder.Folder
+		want     []folder.Folder
+		err      error
+	}{
+		{
+			testName: "Delete folder with nested children",
+			path:     "fold.c1",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "c1", OrgId: validOrgId, Paths: "fold.c1"},
+				{Name: "c2", OrgId: validOrgId, Paths: "fold.c1.c2"},
+				{Name: "c1-sibling", OrgId: validOrgId, Paths: "fold.c1-sibling"},
+			},
+			want: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "c1-sibling", OrgId: validOrgId, Paths: "fold.c1-sibling"},
+			},
+		},
+		{
+			testName: "Same path in another organisation is kept",
+			path:     "fold",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "fold", OrgId: otherOrgId, Paths: "fold"},
+			},
+			want: []folder.Folder{
+				{Name: "fold", OrgId: otherOrgId, Paths: "fold"},
+			},
+		},
+		{
+			testName: "Error: Folder does not exist",
+			path:     "missing",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+			},
+			err: folder.ErrFolderNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			f := folder.NewDriver(tt.folders)
+			folders, err := f.DeleteFolder(validOrgId, tt.path)
+
+			if tt.err == nil {
+				assert.NoError(t, err, tt.testName)
+				assert.Equal(t, tt.want, folders, tt.testName)
+			} else {
+				assert.ErrorIs(t, err, tt.err, tt.testName)
+			}
+		})
+	}
+}
@@ -0,0 +1,370 @@
+package folder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// ChangeKind is what a Change does to a folder
+type ChangeKind string
+
+const (
+	ChangeCreate ChangeKind = "create"
+	ChangeDelete ChangeKind = "delete"
+	ChangeMove   ChangeKind = "move"
+	ChangeRename ChangeKind = "rename"
+)
+
+// Change is one operation on a folder, along with its descendants for every kind but create.
+// Paths are those when the change is made, after the changes before it in its ChangeSet.
+type Change struct {
+	Kind  ChangeKind `json:"kind"`
+	OrgId uuid.UUID  `json:"org_id"`
+
+	// Path is the folder changed, or the folder added by a create.
+	Path string `json:"path"`
+
+	// NewPath is where a move or rename leaves the folder.
+	NewPath string `json:"new_path,omitempty"`
+}
+
+func (c Change) String() string {
+	if c.NewPath != "" {
+		return fmt.Sprintf("%-6s %s -> %s", c.Kind, c.Path, c.NewPath)
+	}
+	return fmt.Sprintf("%-6s %s", c.Kind, c.Path)
+}
+
+// ChangeSet is a list of changes, which turn one set of folders into another when made in order
+type ChangeSet struct {
+	Changes []Change `json:"changes"`
+}
+
+// String renders the changes one per line, beneath the organisation they belong to
+func (cs ChangeSet) String() string {
+	var b strings.Builder
+	var org uuid.UUID
+	for i, c := range cs.Changes {
+		if i == 0 || c.OrgId != org {
+			org = c.OrgId
+			fmt.Fprintf(&b, "org %s\n", org)
+		}
+		fmt.Fprintf(&b, "  %s\n", c)
+	}
+	return b.String()
+}
+
+// Diff works out the changes turning before into after.
+// As folders are only known by their paths, a folder is taken to have moved when one with the same name
+// disappears from elsewhere, and to have been renamed when a sibling sharing its descendants disappears.
+// A moved or renamed folder is a single change, which carries its descendants along.
+// Any other folder which disappears is deleted, and any other which appears is created.
+func Diff(before []Folder, after []Folder) ChangeSet {
+	orgs := []uuid.UUID{}
+	beforeByOrg := map[uuid.UUID][]Folder{}
+	afterByOrg := map[uuid.UUID][]Folder{}
+
+	for _, f := range before {
+		if _, seen := beforeByOrg[f.OrgId]; !seen {
+			orgs = append(orgs, f.OrgId)
+		}
+		beforeByOrg[f.OrgId] = append(beforeByOrg[f.OrgId], f)
+	}
+	for _, f := range after {
+		_, seenBefore := beforeByOrg[f.OrgId]
+		if _, seen := afterByOrg[f.OrgId]; !seen && !seenBefore {
+			orgs = append(orgs, f.OrgId)
+		}
+		afterByOrg[f.OrgId] = append(afterByOrg[f.OrgId], f)
+	}
+
+	changes := []Change{}
+	for _, orgID := range orgs {
+		d := newOrgDiff(orgID, beforeByOrg[orgID], afterByOrg[orgID])
+		d.match()
+		changes = append(changes, d.emit()...)
+	}
+	return ChangeSet{Changes: changes}
+}
+
+// orgDiff works out the changes within one organisation.
+// It first matches the folders appearing in after with those disappearing from before,
+// then replays the matches in an order where each change's paths exist when it is made.
+type orgDiff struct {
+	orgID    uuid.UUID
+	before   []Folder
+	after    []Folder
+	inBefore map[string]bool
+	inAfter  map[string]bool
+
+	// Matching: the before path of each after folder which moved, or was carried along by an ancestor
+	origin  map[string]string
+	kinds   map[string]ChangeKind // of each after folder which is changed itself, rather than carried along
+	changed []string              // the after folders in kinds, parents first
+	moves   map[string]string     // the after path of each before folder which is moved or renamed itself
+	removed map[string]bool       // before folders yet to be matched, which are deleted in the end
+
+	// Replaying: where each before folder is now, and which before folder is at each path now
+	current  map[string]string
+	occupant map[string]string
+	placed   map[string]bool
+	placing  map[string]bool
+	changes  []Change
+}
+
+func newOrgDiff(orgID uuid.UUID, before []Folder, after []Folder) *orgDiff {
+	d := &orgDiff{
+		orgID:    orgID,
+		before:   before,
+		after:    after,
+		inBefore: map[string]bool{},
+		inAfter:  map[string]bool{},
+		origin:   map[string]string{},
+		kinds:    map[string]ChangeKind{},
+		moves:    map[string]string{},
+		removed:  map[string]bool{},
+		current:  map[string]string{},
+		occupant: map[string]string{},
+		placed:   map[string]bool{},
+		placing:  map[string]bool{},
+	}
+
+	for _, f := range before {
+		d.inBefore[f.Paths] = true
+		d.current[f.Paths] = f.Paths
+		d.occupant[f.Paths] = f.Paths
+	}
+	for _, f := range after {
+		d.inAfter[f.Paths] = true
+	}
+	for _, f := range before {
+		if !d.inAfter[f.Paths] {
+			d.removed[f.Paths] = true
+		}
+	}
+
+	return d
+}
+
+// originOf returns the before path of the folder at an after path, if it was there before
+func (d *orgDiff) originOf(path string) (string, bool) {
+	if path == "" || (d.inBefore[path] && d.inAfter[path]) {
+		return path, true
+	}
+	origin, found := d.origin[path]
+	return origin, found
+}
+
+// relatives returns the paths of the descendants of a folder, relative to it
+func relatives(folders []Folder, path string) map[string]bool {
+	result := map[string]bool{}
+	for _, f := range folders {
+		if IsDescendantPath(path, f.Paths) {
+			result[strings.TrimPrefix(f.Paths, path)] = true
+		}
+	}
+	return result
+}
+
+// byDepth returns the folders ordered parents first, otherwise keeping their order
+func byDepth(folders []Folder) []Folder {
+	sorted := append([]Folder{}, folders...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.Count(sorted[i].Paths, ".") < strings.Count(sorted[j].Paths, ".")
+	})
+	return sorted
+}
+
+// match works out where each folder appearing in after came from, parents first
+func (d *orgDiff) match() {
+	for _, a := range byDepth(d.after) {
+		if d.inBefore[a.Paths] {
+			continue
+		}
+
+		parentOrigin, parentExisted := d.originOf(ParentPath(a.Paths))
+
+		// Carried along by its parent
+		if carried := JoinPath(parentOrigin, a.Name); parentExisted && d.removed[carried] {
+			d.matchFolder(a.Paths, carried, "")
+			continue
+		}
+
+		if moved, found := d.findMoved(a); found {
+			d.matchFolder(a.Paths, moved, ChangeMove)
+			continue
+		}
+
+		if parentExisted {
+			if renamed, found := d.findRenamed(a, parentOrigin); found {
+				d.matchFolder(a.Paths, renamed, ChangeRename)
+				continue
+			}
+		}
+
+		d.kinds[a.Paths] = ChangeCreate
+		d.changed = append(d.changed, a.Paths)
+	}
+}
+
+func (d *orgDiff) matchFolder(path string, origin string, kind ChangeKind) {
+	d.origin[path] = origin
+	delete(d.removed, origin)
+	if kind != "" {
+		d.kinds[path] = kind
+		d.changed = append(d.changed, path)
+		d.moves[origin] = path
+	}
+}
+
+// findMoved finds a disappearing folder with the same name as a, preferring the one sharing most descendants
+func (d *orgDiff) findMoved(a Folder) (string, bool) {
+	var afterRelatives map[string]bool = relatives(d.after, a.Paths)
+
+	var best string
+	var bestShared int = -1
+	for _, b := range d.before {
+		if b.Name != a.Name || !d.removed[b.Paths] {
+			continue
+		}
+
+		var shared int = 0
+		for relative := range relatives(d.before, b.Paths) {
+			if afterRelatives[relative] {
+				shared++
+			}
+		}
+		if shared > bestShared {
+			best, bestShared = b.Paths, shared
+		}
+	}
+	return best, bestShared >= 0
+}
+
+// findRenamed finds a disappearing sibling of a, as it was before, sharing the most descendants with it.
+// A sibling sharing none only counts if neither has descendants, as when a leaf folder is renamed.
+func (d *orgDiff) findRenamed(a Folder, parentOrigin string) (string, bool) {
+	var afterRelatives map[string]bool = relatives(d.after, a.Paths)
+
+	var best string
+	var bestShared int = 0
+	for _, b := range d.before {
+		if !d.removed[b.Paths] || ParentPath(b.Paths) != parentOrigin {
+			continue
+		}
+
+		var beforeRelatives map[string]bool = relatives(d.before, b.Paths)
+		if len(beforeRelatives) == 0 && len(afterRelatives) == 0 && best == "" {
+			best = b.Paths
+		}
+
+		var shared int = 0
+		for relative := range beforeRelatives {
+			if afterRelatives[relative] {
+				shared++
+			}
+		}
+		if shared > bestShared {
+			best, bestShared = b.Paths, shared
+		}
+	}
+	return best, best != ""
+}
+
+// emit replays the matches as changes, followed by deleting the folders left unmatched
+func (d *orgDiff) emit() []Change {
+	for _, path := range d.changed {
+		d.place(path)
+	}
+
+	for _, b := range d.before {
+		if d.removed[b.Paths] && !d.removed[ParentPath(b.Paths)] {
+			d.delete(b.Paths)
+		}
+	}
+
+	return d.changes
+}
+
+// place makes the change leaving a folder at path, first clearing whatever is there now
+func (d *orgDiff) place(path string) {
+	if d.placed[path] || d.placing[path] {
+		// Placed already, or a cycle of folders each needing the other's place which cannot be untangled
+		return
+	}
+	d.placing[path] = true
+	defer delete(d.placing, path)
+
+	if _, changed := d.kinds[ParentPath(path)]; changed {
+		d.place(ParentPath(path))
+	}
+
+	if occupant, found := d.occupant[path]; found {
+		d.clear(occupant)
+	}
+
+	switch d.kinds[path] {
+	case ChangeCreate:
+		d.changes = append(d.changes, Change{Kind: ChangeCreate, OrgId: d.orgID, Path: path})
+	case ChangeMove, ChangeRename:
+		var src string = d.current[d.origin[path]]
+		d.changes = append(d.changes, Change{Kind: d.kinds[path], OrgId: d.orgID, Path: src, NewPath: path})
+		d.rebase(src, path)
+	}
+	d.placed[path] = true
+}
+
+// clear moves or deletes the before folder at occupant, or the ancestor which it moves or is deleted along with
+func (d *orgDiff) clear(occupant string) {
+	for ancestor := occupant; ancestor != ""; ancestor = ParentPath(ancestor) {
+		if path, moves := d.moves[ancestor]; moves {
+			d.place(path)
+			return
+		}
+
+		if d.removed[ancestor] && !d.removed[ParentPath(ancestor)] {
+			d.delete(ancestor)
+			return
+		}
+	}
+}
+
+// delete removes a folder which disappears, first placing any descendants which move elsewhere
+func (d *orgDiff) delete(origin string) {
+	if _, exists := d.current[origin]; !exists {
+		return
+	}
+
+	for _, path := range d.changed {
+		if moved, moves := d.origin[path]; moves && IsDescendantPath(origin, moved) {
+			d.place(path)
+		}
+	}
+
+	var path string = d.current[origin]
+	d.changes = append(d.changes, Change{Kind: ChangeDelete, OrgId: d.orgID, Path: path})
+	for b, now := range d.current {
+		if now == path || IsDescendantPath(path, now) {
+			delete(d.current, b)
+			delete(d.occupant, now)
+		}
+	}
+}
+
+// rebase records the folders now at src and beneath it having moved to dst
+func (d *orgDiff) rebase(src string, dst string) {
+	moved := map[string]string{}
+	for b, now := range d.current {
+		if now == src || IsDescendantPath(src, now) {
+			moved[b] = dst + strings.TrimPrefix(now, src)
+			delete(d.occupant, now)
+		}
+	}
+	for b, now := range moved {
+		d.current[b] = now
+		d.occupant[now] = b
+	}
+}
@@ -0,0 +1,305 @@
+package folder_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replay makes changes to folders with a driver
+func replay(t *testing.T, folders []folder.Folder, changes folder.ChangeSet) []folder.Folder {
+	t.Helper()
+
+	d := folder.NewDriver(folders)
+	require.NoError(t, folder.ApplyPatch(d, changes))
+
+	var orgs []uuid.UUID
+	seen := map[uuid.UUID]bool{}
+	for _, f := range folders {
+		if !seen[f.OrgId] {
+			orgs = append(orgs, f.OrgId)
+			seen[f.OrgId] = true
+		}
+	}
+	for _, c := range changes.Changes {
+		if !seen[c.OrgId] {
+			orgs = append(orgs, c.OrgId)
+			seen[c.OrgId] = true
+		}
+	}
+
+	result := []folder.Folder{}
+	for _, orgId := range orgs {
+		result = append(result, d.GetFoldersByOrgID(orgId)...)
+	}
+	return result
+}
+
+func Test_folder_Diff(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+	tests := [...]struct {
+		testName string
+		before   []folder.Folder
+		after    []folder.Folder
+		want     []folder.Change
+	}{
+		{
+			testName: "No changes",
+			before: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+			},
+			after: []folder.Folder{
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+			},
+			want: []folder.Change{},
+		},
+		{
+			testName: "Moved subtree is one move",
+			before: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "alpha.bravo.delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "echo"},
+			},
+			after: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "echo.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "echo.bravo.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "echo.bravo.delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "echo"},
+			},
+			want: []folder.Change{
+				{Kind: folder.ChangeMove, OrgId: validOrgId, Path: "alpha.bravo", NewPath: "echo.bravo"},
+			},
+		},
+		{
+			testName: "Renamed subtree is one rename",
+			before: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+			},
+			after: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "zulu", OrgId: validOrgId, Paths: "alpha.zulu"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.zulu.charlie"},
+			},
+			want: []folder.Change{
+				{Kind: folder.ChangeRename, OrgId: validOrgId, Path: "alpha.bravo", NewPath: "alpha.zulu"},
+			},
+		},
+		{
+			testName: "Created and deleted subtrees",
+			before: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+			},
+			after: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "delta.echo"},
+			},
+			want: []folder.Change{
+				{Kind: folder.ChangeCreate, OrgId: validOrgId, Path: "delta"},
+				{Kind: folder.ChangeCreate, OrgId: validOrgId, Path: "delta.echo"},
+				{Kind: folder.ChangeDelete, OrgId: validOrgId, Path: "alpha.bravo"},
+			},
+		},
+		{
+			testName: "Moved into a created folder, out of a deleted one",
+			before: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+				{Name: "echo", OrgId: validOrgId, Paths: "echo"},
+			},
+			after: []folder.Folder{
+				{Name: "echo", OrgId: validOrgId, Paths: "echo"},
+				{Name: "delta", OrgId: validOrgId, Paths: "echo.delta"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "echo.delta.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "echo.delta.bravo.charlie"},
+			},
+			want: []folder.Change{
+				{Kind: folder.ChangeCreate, OrgId: validOrgId, Path: "echo.delta"},
+				{Kind: folder.ChangeMove, OrgId: validOrgId, Path: "alpha.bravo", NewPath: "echo.delta.bravo"},
+				{Kind: folder.ChangeDelete, OrgId: validOrgId, Path: "alpha"},
+			},
+		},
+		{
+			testName: "Renamed root losing a descendant",
+			before: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "xray", OrgId: validOrgId, Paths: "alpha.xray"},
+			},
+			after: []folder.Folder{
+				{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "delta.bravo"},
+			},
+			want: []folder.Change{
+				{Kind: folder.ChangeRename, OrgId: validOrgId, Path: "alpha", NewPath: "delta"},
+				{Kind: folder.ChangeDelete, OrgId: validOrgId, Path: "delta.xray"},
+			},
+		},
+		{
+			testName: "Renamed root with the same descendants",
+			before: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+			},
+			after: []folder.Folder{
+				{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "delta.bravo"},
+			},
+			want: []folder.Change{
+				{Kind: folder.ChangeRename, OrgId: validOrgId, Path: "alpha", NewPath: "delta"},
+			},
+		},
+		{
+			testName: "Moved out of a folder which is then renamed",
+			before: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "alpha.bravo.delta"},
+			},
+			after: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "zulu", OrgId: validOrgId, Paths: "alpha.zulu"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.zulu.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "alpha.delta"},
+			},
+			want: []folder.Change{
+				{Kind: folder.ChangeRename, OrgId: validOrgId, Path: "alpha.bravo", NewPath: "alpha.zulu"},
+				{Kind: folder.ChangeMove, OrgId: validOrgId, Path: "alpha.zulu.delta", NewPath: "alpha.delta"},
+			},
+		},
+		{
+			testName: "Moves in one organisation do not match folders in another",
+			before: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: otherOrgId, Paths: "charlie"},
+			},
+			after: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "charlie", OrgId: otherOrgId, Paths: "charlie"},
+				{Name: "bravo", OrgId: otherOrgId, Paths: "charlie.bravo"},
+			},
+			want: []folder.Change{
+				{Kind: folder.ChangeDelete, OrgId: validOrgId, Path: "alpha.bravo"},
+				{Kind: folder.ChangeCreate, OrgId: otherOrgId, Path: "charlie.bravo"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			got := folder.Diff(tt.before, tt.after)
+			assert.Equal(t, tt.want, got.Changes)
+			assert.ElementsMatch(t, tt.after, replay(t, tt.before, got), "Making the changes turns before into after")
+		})
+	}
+}
+
+func Test_folder_Diff_MoveFolder(t *testing.T) {
+	t.Parallel()
+
+	before := folder.GetSampleData()
+	d := folder.NewDriver(before)
+	after, err := d.MoveFolderByPath(uuid.FromStringOrNil(folder.DefaultOrgID), "stunning-horridus.pure-blastaar", "noble-vixen")
+	require.NoError(t, err)
+
+	changes := folder.Diff(before, after)
+	assert.Equal(t, []folder.Change{{
+		Kind:    folder.ChangeMove,
+		OrgId:   uuid.FromStringOrNil(folder.DefaultOrgID),
+		Path:    "stunning-horridus.pure-blastaar",
+		NewPath: "noble-vixen.pure-blastaar",
+	}}, changes.Changes)
+}
+
+func Test_folder_ChangeSet_Rendering(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+
+	changes := folder.ChangeSet{Changes: []folder.Change{
+		{Kind: folder.ChangeCreate, OrgId: validOrgId, Path: "delta"},
+		{Kind: folder.ChangeMove, OrgId: validOrgId, Path: "alpha.bravo", NewPath: "delta.bravo"},
+		{Kind: folder.ChangeDelete, OrgId: validOrgId, Path: "alpha"},
+	}}
+
+	assert.Equal(t, ""+
+		"org c59cc5c1-9b81-4d00-95e3-22c6efdaf134\n"+
+		"  create delta\n"+
+		"  move   alpha.bravo -> delta.bravo\n"+
+		"  delete alpha\n", changes.String())
+
+	encoded, err := json.Marshal(changes)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"changes": [
+		{"kind": "create", "org_id": "c59cc5c1-9b81-4d00-95e3-22c6efdaf134", "path": "delta"},
+		{"kind": "move", "org_id": "c59cc5c1-9b81-4d00-95e3-22c6efdaf134", "path": "alpha.bravo", "new_path": "delta.bravo"},
+		{"kind": "delete", "org_id": "c59cc5c1-9b81-4d00-95e3-22c6efdaf134", "path": "alpha"}
+	]}`, string(encoded))
+
+	var decoded folder.ChangeSet
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, changes, decoded)
+}
+
+// randomEdits makes random changes to an organisation's folders, ignoring those the driver refuses
+func randomEdits(d folder.IStatefulDriver, orgId uuid.UUID, rng *rand.Rand, edits int) {
+	for i := 0; i < edits; i++ {
+		folders := d.GetFoldersByOrgID(orgId)
+		if len(folders) == 0 {
+			return
+		}
+
+		var src, dst folder.Folder = folders[rng.Intn(len(folders))], folders[rng.Intn(len(folders))]
+		switch rng.Intn(4) {
+		case 0:
+			d.CreateFolder(orgId, fmt.Sprintf("new-%d", i), src.Paths)
+		case 1:
+			d.DeleteFolder(orgId, src.Paths)
+		case 2:
+			d.MoveFolderByPath(orgId, src.Paths, dst.Paths)
+		case 3:
+			d.RenameFolder(orgId, src.Paths, fmt.Sprintf("renamed-%d", i))
+		}
+	}
+}
+
+// Test_folder_Diff_Replay makes random changes to the sample data, checking its diff makes the same changes
+func Test_folder_Diff_Replay(t *testing.T) {
+	t.Parallel()
+
+	var orgId = uuid.FromStringOrNil(folder.DefaultOrgID)
+	var before []folder.Folder = folder.NewDriver(folder.GetSampleData()).GetFoldersByOrgID(orgId)
+	rng := rand.New(rand.NewSource(1))
+
+	for round := 0; round < 20; round++ {
+		d := folder.NewDriver(before)
+		randomEdits(d, orgId, rng, 10)
+
+		var after []folder.Folder = d.GetFoldersByOrgID(orgId)
+		assert.ElementsMatch(t, after, replay(t, before, folder.Diff(before, after)), "Round %d", round)
+	}
+}
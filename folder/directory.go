@@ -0,0 +1,136 @@
+package folder
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// ErrUnsafeDirectoryName is returned when a label would name a directory outside of the export root
+var ErrUnsafeDirectoryName = errors.New("label does not name a safe directory")
+
+// ImportFromDirectory walks the directory tree beneath root, returning a folder for each directory within it.
+// Directory names are sanitised into labels with SanitizeLabel, and files and symlinks are ignored.
+func ImportFromDirectory(root string, orgID uuid.UUID) ([]Folder, error) {
+	folders := []Folder{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || path == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		labels := strings.Split(rel, string(filepath.Separator))
+		for i, name := range labels {
+			labels[i] = SanitizeLabel(name)
+		}
+
+		folders = append(folders, Folder{
+			Name:  labels[len(labels)-1],
+			OrgId: orgID,
+			Paths: strings.Join(labels, "."),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+// ExportToDirectory creates a directory beneath root for each folder, named by reversing SanitizeLabel.
+// As directories carry no organisation, folders must all belong to the same one.
+func ExportToDirectory(folders []Folder, root string) error {
+	for _, f := range folders {
+		if f.OrgId != folders[0].OrgId {
+			return fmt.Errorf("folders belong to more than one organization: %s and %s", folders[0].OrgId, f.OrgId)
+		}
+	}
+
+	for _, f := range folders {
+		var path string = root
+		for _, label := range strings.Split(f.Paths, ".") {
+			name, err := UnsanitizeLabel(label)
+			if err != nil {
+				return fmt.Errorf("export %q: %w", f.Paths, err)
+			}
+
+			if name == "." || name == ".." || strings.ContainsAny(name, "/\\\x00") {
+				return fmt.Errorf("export %q: %w: %q", f.Paths, ErrUnsafeDirectoryName, name)
+			}
+
+			path = filepath.Join(path, name)
+		}
+
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SanitizeLabel maps a name onto a valid ltree label.
+// Letters, digits, '-' and '_' are kept, and every other byte becomes '_' followed by its two hex digits,
+// so the mapping is reversed by UnsanitizeLabel. A '_' which would read as such an escape is escaped itself.
+func SanitizeLabel(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		var c byte = name[i]
+		if isLabelByte(c) || c == '_' && !isEscape(name[i:]) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "_%02x", c)
+		}
+	}
+	return b.String()
+}
+
+// UnsanitizeLabel recovers the name a label was sanitised from.
+// Only the escapes SanitizeLabel writes are decoded, so labels such as child_path_1 are their own names.
+func UnsanitizeLabel(label string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(label); i++ {
+		var c byte = label[i]
+		if isEscape(label[i:]) {
+			v, _ := strconv.ParseUint(label[i+1:i+3], 16, 8)
+			b.WriteByte(byte(v))
+			i += 2
+			continue
+		}
+
+		if !isLabelByte(c) && c != '_' {
+			return "", fmt.Errorf("%q is not a sanitised label", label)
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), nil
+}
+
+// isEscape checks if s starts with an escape SanitizeLabel writes: '_' and the lower case hex digits of a byte it does not keep
+func isEscape(s string) bool {
+	if len(s) < 3 || s[0] != '_' {
+		return false
+	}
+	v, err := strconv.ParseUint(s[1:3], 16, 8)
+	return err == nil && fmt.Sprintf("%02x", v) == s[1:3] && !isLabelByte(byte(v))
+}
+
+// isLabelByte checks if a byte is kept as is by SanitizeLabel
+func isLabelByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-'
+}
@@ -0,0 +1,150 @@
+package folder_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_folder_SanitizeLabel(t *testing.T) {
+	t.Parallel()
+
+	tests := [...]struct {
+		testName string
+		name     string
+		label    string
+	}{
+		{testName: "Valid label unchanged", name: "creative-scalphunter", label: "creative-scalphunter"},
+		{testName: "Spaces and dots escaped", name: "Q1 report.v2", label: "Q1_20report_2ev2"},
+		{testName: "Underscore kept", name: "child_path_1", label: "child_path_1"},
+		{testName: "Underscore reading as an escape escaped", name: "a_2f_5f", label: "a_5f2f_5f5f"},
+		{testName: "Underscore ahead of a kept byte's hex kept", name: "letter_61_2E", label: "letter_61_2E"},
+		{testName: "Unicode escaped per byte", name: "café", label: "caf_c3_a9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			assert.Equal(t, tt.label, folder.SanitizeLabel(tt.name), tt.testName)
+
+			name, err := folder.UnsanitizeLabel(tt.label)
+			assert.NoError(t, err, tt.testName)
+			assert.Equal(t, tt.name, name, tt.testName)
+		})
+	}
+}
+
+func Test_folder_UnsanitizeLabel_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, label := range []string{"dot.ted", "sp ace", "caf\xc3\xa9"} {
+		_, err := folder.UnsanitizeLabel(label)
+		assert.Error(t, err, label)
+	}
+}
+
+func Test_folder_ImportFromDirectory(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "alpha", "bravo", "charlie"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "alpha", "my docs"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "echo"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "alpha", "file.txt"), nil, 0o644))
+	require.NoError(t, os.Symlink(filepath.Join(root, "echo"), filepath.Join(root, "alpha", "link")))
+
+	folders, err := folder.ImportFromDirectory(root, validOrgId)
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+		{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+		{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+		{Name: "my_20docs", OrgId: validOrgId, Paths: "alpha.my_20docs"},
+		{Name: "echo", OrgId: validOrgId, Paths: "echo"},
+	}, folders)
+
+	exported := t.TempDir()
+	require.NoError(t, folder.ExportToDirectory(folders, exported))
+	assert.DirExists(t, filepath.Join(exported, "alpha", "my docs"))
+
+	reimported, err := folder.ImportFromDirectory(exported, validOrgId)
+	assert.NoError(t, err)
+	assert.Equal(t, folders, reimported, "Round trips")
+}
+
+func Test_folder_ExportToDirectory_Underscores(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	folders := []folder.Folder{
+		{Name: "invalid_folder", OrgId: validOrgId, Paths: "invalid_folder"},
+		{Name: "child_path_1", OrgId: validOrgId, Paths: "invalid_folder.child_path_1"},
+		{Name: "my_20docs", OrgId: validOrgId, Paths: "invalid_folder.my_20docs"},
+	}
+
+	root := t.TempDir()
+	require.NoError(t, folder.ExportToDirectory(folders, root))
+	assert.DirExists(t, filepath.Join(root, "invalid_folder", "child_path_1"))
+	assert.DirExists(t, filepath.Join(root, "invalid_folder", "my docs"))
+
+	reimported, err := folder.ImportFromDirectory(root, validOrgId)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, folders, reimported, "Round trips")
+}
+
+func Test_folder_ImportFromDirectory_MissingRoot(t *testing.T) {
+	t.Parallel()
+
+	_, err := folder.ImportFromDirectory(filepath.Join(t.TempDir(), "missing"), uuid.Nil)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func Test_folder_ExportToDirectory_Errors(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+	tests := [...]struct {
+		testName string
+		folders  []folder.Folder
+		err      error
+	}{
+		{
+			testName: "Parent directory label",
+			folders:  []folder.Folder{{Name: "_2e_2e", OrgId: validOrgId, Paths: "_2e_2e"}},
+			err:      folder.ErrUnsafeDirectoryName,
+		},
+		{
+			testName: "Label with a separator",
+			folders:  []folder.Folder{{Name: "a_2fb", OrgId: validOrgId, Paths: "a_2fb"}},
+			err:      folder.ErrUnsafeDirectoryName,
+		},
+		{
+			testName: "More than one organisation",
+			folders: []folder.Folder{
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+				{Name: "b", OrgId: otherOrgId, Paths: "b"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			root := t.TempDir()
+			err := folder.ExportToDirectory(tt.folders, root)
+
+			assert.Error(t, err, tt.testName)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err, tt.testName)
+			}
+
+			entries, _ := os.ReadDir(root)
+			assert.Empty(t, entries, tt.testName)
+		})
+	}
+}
@@ -0,0 +1,442 @@
+// Package drivertest holds every folder.IDriver to the same behaviour: that of the README's scenarios
+// and the in-memory driver's tests. Call RunConformance from a test of each implementation.
+//
+// Folder order is not part of the behaviour, as the README leaves it open, so results are compared as sets.
+// MoveFolder need only return the folders of the organisation moved within, as folder.IDriver allows,
+// but must keep the move, so later reads see it.
+package drivertest
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// OrgID and OtherOrgID are the organisations of the README's scenarios, where foxtrot alone belongs to OtherOrgID
+var (
+	OrgID      = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	OtherOrgID = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+)
+
+// childScenario is the README's scenario for GetAllChildFolders
+var childScenario = []folder.Folder{
+	{Name: "alpha", OrgId: OrgID, Paths: "alpha"},
+	{Name: "bravo", OrgId: OrgID, Paths: "alpha.bravo"},
+	{Name: "charlie", OrgId: OrgID, Paths: "alpha.bravo.charlie"},
+	{Name: "delta", OrgId: OrgID, Paths: "alpha.delta"},
+	{Name: "echo", OrgId: OrgID, Paths: "echo"},
+	{Name: "foxtrot", OrgId: OtherOrgID, Paths: "foxtrot"},
+}
+
+// moveScenario is the README's scenario for MoveFolder
+var moveScenario = []folder.Folder{
+	{Name: "alpha", OrgId: OrgID, Paths: "alpha"},
+	{Name: "bravo", OrgId: OrgID, Paths: "alpha.bravo"},
+	{Name: "charlie", OrgId: OrgID, Paths: "alpha.bravo.charlie"},
+	{Name: "delta", OrgId: OrgID, Paths: "alpha.delta"},
+	{Name: "echo", OrgId: OrgID, Paths: "alpha.delta.echo"},
+	{Name: "foxtrot", OrgId: OtherOrgID, Paths: "foxtrot"},
+	{Name: "golf", OrgId: OrgID, Paths: "golf"},
+}
+
+// ChildScenario returns the folders of the README's scenario for GetAllChildFolders, as a fresh copy callers may keep
+func ChildScenario() []folder.Folder {
+	return append([]folder.Folder{}, childScenario...)
+}
+
+// MoveScenario returns the folders of the README's scenario for MoveFolder, as a fresh copy callers may keep
+func MoveScenario() []folder.Folder {
+	return append([]folder.Folder{}, moveScenario...)
+}
+
+// NewChildDriver returns an in-memory driver over ChildScenario, for the tests of drivers which wrap another
+func NewChildDriver() folder.IObservableDriver {
+	return folder.NewDriver(ChildScenario())
+}
+
+// NewMoveDriver returns an in-memory driver over MoveScenario, for the tests of drivers which wrap another
+func NewMoveDriver() folder.IObservableDriver {
+	return folder.NewDriver(MoveScenario())
+}
+
+// RunConformance checks the drivers made by newDriver behave as IDriver requires.
+// newDriver is called afresh for each case, and must return a driver holding only the given folders,
+// which it may keep but must not change.
+func RunConformance(t *testing.T, newDriver func([]folder.Folder) folder.IDriver) {
+	t.Run("GetFoldersByOrgID", func(t *testing.T) {
+		t.Parallel()
+		testGetFoldersByOrgID(t, newDriver)
+	})
+	t.Run("GetAllChildFolders", func(t *testing.T) {
+		t.Parallel()
+		testGetAllChildFolders(t, newDriver)
+	})
+	t.Run("MoveFolder", func(t *testing.T) {
+		t.Parallel()
+		testMoveFolder(t, newDriver)
+	})
+}
+
+// fresh makes a driver from a copy of folders, so no driver can change another case's folders
+func fresh(newDriver func([]folder.Folder) folder.IDriver, folders []folder.Folder) folder.IDriver {
+	return newDriver(append([]folder.Folder{}, folders...))
+}
+
+func testGetFoldersByOrgID(t *testing.T, newDriver func([]folder.Folder) folder.IDriver) {
+	tests := [...]struct {
+		testName string
+		folders  []folder.Folder
+		orgID    uuid.UUID
+		want     []folder.Folder
+	}{
+		{
+			testName: "All folders of the organisation",
+			folders:  childScenario,
+			orgID:    OrgID,
+			want:     childScenario[:5],
+		},
+		{
+			testName: "Only folders of the organisation",
+			folders:  childScenario,
+			orgID:    OtherOrgID,
+			want:     childScenario[5:],
+		},
+		{
+			testName: "Unknown organisation gives no folders",
+			folders:  childScenario,
+			orgID:    uuid.FromStringOrNil("38b9879b-f73b-4b0e-b9d9-4fc4c23643a7"),
+			want:     []folder.Folder{},
+		},
+		{
+			testName: "No folders gives no folders",
+			folders:  []folder.Folder{},
+			orgID:    OrgID,
+			want:     []folder.Folder{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			assert.ElementsMatch(t, tt.want, fresh(newDriver, tt.folders).GetFoldersByOrgID(tt.orgID))
+		})
+	}
+}
+
+func testGetAllChildFolders(t *testing.T, newDriver func([]folder.Folder) folder.IDriver) {
+	tests := [...]struct {
+		testName string
+		folders  []folder.Folder
+		name     string
+		want     []folder.Folder
+		err      error
+	}{
+		{
+			testName: "README: Children and grandchildren",
+			folders:  childScenario,
+			name:     "alpha",
+			want:     childScenario[1:4],
+		},
+		{
+			testName: "README: Child of a child",
+			folders:  childScenario,
+			name:     "bravo",
+			want:     childScenario[2:3],
+		},
+		{
+			testName: "README: Leaf beneath a root",
+			folders:  childScenario,
+			name:     "charlie",
+			want:     []folder.Folder{},
+		},
+		{
+			testName: "README: Root without children",
+			folders:  childScenario,
+			name:     "echo",
+			want:     []folder.Folder{},
+		},
+		{
+			testName: "README: Error: Folder does not exist",
+			folders:  childScenario,
+			name:     "invalid_folder",
+			err:      folder.ErrFolderNotFound,
+		},
+		{
+			testName: "README: Error: Folder does not exist in the specified organization",
+			folders:  childScenario,
+			name:     "foxtrot",
+			err:      folder.ErrFolderNotInOrg,
+		},
+		{
+			testName: "Children of a folder of the same name in another organisation are not returned",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: OrgID, Paths: "fold"},
+				{Name: "fold", OrgId: OtherOrgID, Paths: "fold"},
+				{Name: "c1", OrgId: OtherOrgID, Paths: "fold.c1"},
+			},
+			name: "fold",
+			want: []folder.Folder{},
+		},
+		{
+			testName: "Children of each folder of the name are returned",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: OrgID, Paths: "fold"},
+				{Name: "new-fold", OrgId: OrgID, Paths: "new-fold"},
+				{Name: "c1", OrgId: OrgID, Paths: "fold.c1"},
+				{Name: "c1", OrgId: OrgID, Paths: "new-fold.c1"},
+				{Name: "child-path-1", OrgId: OrgID, Paths: "fold.c1.child-path-1"},
+				{Name: "child-path-2", OrgId: OrgID, Paths: "new-fold.c1.child-path-2"},
+			},
+			name: "c1",
+			want: []folder.Folder{
+				{Name: "child-path-1", OrgId: OrgID, Paths: "fold.c1.child-path-1"},
+				{Name: "child-path-2", OrgId: OrgID, Paths: "new-fold.c1.child-path-2"},
+			},
+		},
+		{
+			testName: "Children of nested folders of the name are returned once",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: OrgID, Paths: "fold"},
+				{Name: "c1", OrgId: OrgID, Paths: "fold.c1"},
+				{Name: "fold", OrgId: OrgID, Paths: "fold.c1.fold"},
+				{Name: "c2", OrgId: OrgID, Paths: "fold.c1.fold.c2"},
+			},
+			name: "fold",
+			want: []folder.Folder{
+				{Name: "c1", OrgId: OrgID, Paths: "fold.c1"},
+				{Name: "fold", OrgId: OrgID, Paths: "fold.c1.fold"},
+				{Name: "c2", OrgId: OrgID, Paths: "fold.c1.fold.c2"},
+			},
+		},
+		{
+			testName: "Folders whose names begin with the folder's are not children",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: OrgID, Paths: "fold"},
+				{Name: "c1", OrgId: OrgID, Paths: "fold.c1"},
+				{Name: "folder", OrgId: OrgID, Paths: "folder"},
+				{Name: "c2", OrgId: OrgID, Paths: "folder.c2"},
+			},
+			name: "fold",
+			want: []folder.Folder{
+				{Name: "c1", OrgId: OrgID, Paths: "fold.c1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			folders, err := fresh(newDriver, tt.folders).GetAllChildFolders(OrgID, tt.name)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, folders)
+		})
+	}
+}
+
+func testMoveFolder(t *testing.T, newDriver func([]folder.Folder) folder.IDriver) {
+	tests := [...]struct {
+		testName string
+		folders  []folder.Folder
+		src      string
+		dst      string
+		want     []folder.Folder
+		err      error
+	}{
+		{
+			testName: "README: Move to a sibling",
+			folders:  moveScenario,
+			src:      "bravo",
+			dst:      "delta",
+			want: []folder.Folder{
+				{Name: "alpha", OrgId: OrgID, Paths: "alpha"},
+				{Name: "bravo", OrgId: OrgID, Paths: "alpha.delta.bravo"},
+				{Name: "charlie", OrgId: OrgID, Paths: "alpha.delta.bravo.charlie"},
+				{Name: "delta", OrgId: OrgID, Paths: "alpha.delta"},
+				{Name: "echo", OrgId: OrgID, Paths: "alpha.delta.echo"},
+				{Name: "golf", OrgId: OrgID, Paths: "golf"},
+			},
+		},
+		{
+			testName: "README: Move to another root",
+			folders:  moveScenario,
+			src:      "bravo",
+			dst:      "golf",
+			want: []folder.Folder{
+				{Name: "alpha", OrgId: OrgID, Paths: "alpha"},
+				{Name: "bravo", OrgId: OrgID, Paths: "golf.bravo"},
+				{Name: "charlie", OrgId: OrgID, Paths: "golf.bravo.charlie"},
+				{Name: "delta", OrgId: OrgID, Paths: "alpha.delta"},
+				{Name: "echo", OrgId: OrgID, Paths: "alpha.delta.echo"},
+				{Name: "golf", OrgId: OrgID, Paths: "golf"},
+			},
+		},
+		{
+			testName: "README: Error: Cannot move a folder to a child of itself",
+			folders:  moveScenario,
+			src:      "bravo",
+			dst:      "charlie",
+			err:      folder.ErrMoveToChild,
+		},
+		{
+			testName: "README: Error: Cannot move a folder to itself",
+			folders:  moveScenario,
+			src:      "bravo",
+			dst:      "bravo",
+			err:      folder.ErrMoveToSelf,
+		},
+		{
+			testName: "README: Error: Cannot move a folder to a different organization",
+			folders:  moveScenario,
+			src:      "bravo",
+			dst:      "foxtrot",
+			err:      folder.ErrMoveToOtherOrg,
+		},
+		{
+			testName: "README: Error: Source folder does not exist",
+			folders:  moveScenario,
+			src:      "invalid_folder",
+			dst:      "delta",
+			err:      folder.ErrSourceNotFound,
+		},
+		{
+			testName: "README: Error: Destination folder does not exist",
+			folders:  moveScenario,
+			src:      "bravo",
+			dst:      "invalid_folder",
+			err:      folder.ErrDestinationNotFound,
+		},
+		{
+			testName: "Move a root beneath another root",
+			folders:  moveScenario,
+			src:      "golf",
+			dst:      "alpha",
+			want: []folder.Folder{
+				{Name: "alpha", OrgId: OrgID, Paths: "alpha"},
+				{Name: "bravo", OrgId: OrgID, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: OrgID, Paths: "alpha.bravo.charlie"},
+				{Name: "delta", OrgId: OrgID, Paths: "alpha.delta"},
+				{Name: "echo", OrgId: OrgID, Paths: "alpha.delta.echo"},
+				{Name: "golf", OrgId: OrgID, Paths: "alpha.golf"},
+			},
+		},
+		{
+			testName: "Move a grandchild beneath its grandparent",
+			folders:  moveScenario,
+			src:      "charlie",
+			dst:      "alpha",
+			want: []folder.Folder{
+				{Name: "alpha", OrgId: OrgID, Paths: "alpha"},
+				{Name: "bravo", OrgId: OrgID, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: OrgID, Paths: "alpha.charlie"},
+				{Name: "delta", OrgId: OrgID, Paths: "alpha.delta"},
+				{Name: "echo", OrgId: OrgID, Paths: "alpha.delta.echo"},
+				{Name: "golf", OrgId: OrgID, Paths: "golf"},
+			},
+		},
+		{
+			testName: "Move to the folder's own parent changes nothing",
+			folders:  moveScenario,
+			src:      "bravo",
+			dst:      "alpha",
+			want:     append(append([]folder.Folder{}, moveScenario[:5]...), moveScenario[6]),
+		},
+		{
+			testName: "Folders whose names begin with the source's stay put",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: OrgID, Paths: "fold"},
+				{Name: "c1", OrgId: OrgID, Paths: "fold.c1"},
+				{Name: "folder", OrgId: OrgID, Paths: "folder"},
+				{Name: "c2", OrgId: OrgID, Paths: "folder.c2"},
+				{Name: "a", OrgId: OrgID, Paths: "a"},
+			},
+			src: "fold",
+			dst: "a",
+			want: []folder.Folder{
+				{Name: "fold", OrgId: OrgID, Paths: "a.fold"},
+				{Name: "c1", OrgId: OrgID, Paths: "a.fold.c1"},
+				{Name: "folder", OrgId: OrgID, Paths: "folder"},
+				{Name: "c2", OrgId: OrgID, Paths: "folder.c2"},
+				{Name: "a", OrgId: OrgID, Paths: "a"},
+			},
+		},
+		{
+			testName: "Move into a folder whose name begins with the source's",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: OrgID, Paths: "fold"},
+				{Name: "folder", OrgId: OrgID, Paths: "folder"},
+			},
+			src: "fold",
+			dst: "folder",
+			want: []folder.Folder{
+				{Name: "fold", OrgId: OrgID, Paths: "folder.fold"},
+				{Name: "folder", OrgId: OrgID, Paths: "folder"},
+			},
+		},
+		{
+			testName: "Folders of another organisation stay put",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: OrgID, Paths: "fold"},
+				{Name: "c1", OrgId: OrgID, Paths: "fold.c1"},
+				{Name: "a", OrgId: OrgID, Paths: "a"},
+				{Name: "fold", OrgId: OtherOrgID, Paths: "fold"},
+				{Name: "c1", OrgId: OtherOrgID, Paths: "fold.c1"},
+			},
+			src: "fold",
+			dst: "a",
+			want: []folder.Folder{
+				{Name: "fold", OrgId: OrgID, Paths: "a.fold"},
+				{Name: "c1", OrgId: OrgID, Paths: "a.fold.c1"},
+				{Name: "a", OrgId: OrgID, Paths: "a"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			d := fresh(newDriver, tt.folders)
+			folders, err := d.MoveFolder(tt.src, tt.dst)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.ElementsMatch(t, orgFolders(tt.folders, OrgID), d.GetFoldersByOrgID(OrgID), "A failed move changes nothing")
+				return
+			}
+			require.NoError(t, err)
+
+			// Other organisations' folders may or may not be returned, but must be untouched if they are
+			moved := []folder.Folder{}
+			for _, f := range folders {
+				if f.OrgId == OrgID {
+					moved = append(moved, f)
+				} else {
+					assert.Contains(t, tt.folders, f)
+				}
+			}
+			assert.ElementsMatch(t, tt.want, moved)
+
+			assert.ElementsMatch(t, tt.want, d.GetFoldersByOrgID(OrgID), "The move is kept")
+			assert.ElementsMatch(t, orgFolders(tt.folders, OtherOrgID), d.GetFoldersByOrgID(OtherOrgID))
+		})
+	}
+}
+
+// orgFolders returns the folders belonging to an organisation
+func orgFolders(folders []folder.Folder, orgID uuid.UUID) []folder.Folder {
+	result := []folder.Folder{}
+	for _, f := range folders {
+		if f.OrgId == orgID {
+			result = append(result, f)
+		}
+	}
+	return result
+}
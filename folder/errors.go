@@ -0,0 +1,26 @@
+package folder
+
+import "errors"
+
+// Errors returned by IDriver implementations, so callers can tell failures apart with errors.Is
+var (
+	ErrFolderNotFound      = errors.New("folder does not exist")
+	ErrFolderNotInOrg      = errors.New("folder does not exist in the specified organization")
+	ErrSourceNotFound      = errors.New("source folder does not exist")
+	ErrDestinationNotFound = errors.New("destination folder does not exist")
+	ErrMoveToSelf          = errors.New("cannot move a folder to itself")
+	ErrMoveToChild         = errors.New("cannot move a folder to a child of itself")
+	ErrMoveToOtherOrg      = errors.New("cannot move a folder to a different organization")
+	ErrFolderExists        = errors.New("folder already exists")
+	ErrInvalidName         = errors.New("folder name must be a non-empty label without dots")
+	ErrInvalidPath         = errors.New("folder path must be non-empty labels separated by dots, ending in the folder name")
+	ErrVersionConflict     = errors.New("folders have changed since the expected version")
+	ErrInvalidChange       = errors.New("change must be a create, delete, move keeping the name, or rename keeping the parent")
+	ErrTooManyFolders      = errors.New("organisation would exceed its maximum number of folders")
+	ErrTooDeep             = errors.New("folder would exceed the maximum depth")
+	ErrTooManyChildren     = errors.New("folder would exceed its maximum number of children")
+	ErrPathTooLong         = errors.New("folder path would exceed the maximum length")
+	ErrInvalidLimits       = errors.New("limits must not be negative")
+	ErrLimitsUnsupported   = errors.New("limits need a driver made by NewDriver, NewConcurrentDriver or NewShardedDriver")
+	ErrInvalidConfig       = errors.New("generator config needs an organisation, a depth of at least one, 0 <= MinChildren <= MaxChildren, no negative roots and a collision rate within [0, 1]")
+)
@@ -0,0 +1,209 @@
+package folder
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/gofrs/uuid"
+)
+
+// SubscriptionBuffer is how many events a subscriber may fall behind by before it is dropped
+const SubscriptionBuffer = 256
+
+// Event is a change to one folder, published once the mutation making it has succeeded.
+// It is one of FolderCreated, FolderMoved, FolderRenamed or FolderDeleted.
+type Event interface {
+	// Org returns the organisation of the changed folder.
+	Org() uuid.UUID
+
+	// Path returns the path of the changed folder, or where it was if it has been deleted.
+	Path() string
+}
+
+// FolderCreated is published for a new folder
+type FolderCreated struct {
+	Folder  Folder
+	Version uint64
+}
+
+// FolderMoved is published for each folder whose path changed, but not its name,
+// including the descendants of a moved or renamed folder
+type FolderMoved struct {
+	Folder  Folder
+	OldPath string
+	NewPath string
+	Version uint64
+}
+
+// FolderRenamed is published for a renamed folder
+type FolderRenamed struct {
+	Folder  Folder
+	OldName string
+	OldPath string
+	NewPath string
+	Version uint64
+}
+
+// FolderDeleted is published for a deleted folder and each of its descendants
+type FolderDeleted struct {
+	Folder  Folder
+	Version uint64
+}
+
+func (e FolderCreated) Org() uuid.UUID { return e.Folder.OrgId }
+func (e FolderCreated) Path() string   { return e.Folder.Paths }
+func (e FolderMoved) Org() uuid.UUID   { return e.Folder.OrgId }
+func (e FolderMoved) Path() string     { return e.NewPath }
+func (e FolderRenamed) Org() uuid.UUID { return e.Folder.OrgId }
+func (e FolderRenamed) Path() string   { return e.NewPath }
+func (e FolderDeleted) Org() uuid.UUID { return e.Folder.OrgId }
+func (e FolderDeleted) Path() string   { return e.Folder.Paths }
+
+// Publisher delivers events to the subscribers of their organisation, and to those of uuid.Nil, who receive every organisation's.
+// Publishing never blocks: a subscriber whose buffer is full is dropped, closing its channel,
+// so a closed channel tells the subscriber it has missed events and should read the folders again.
+type Publisher struct {
+	mu    sync.Mutex
+	subs  map[uuid.UUID]map[*subscription]struct{}
+	count atomic.Int64 // of subscriptions, so publishing with none takes no lock
+}
+
+type subscription struct {
+	events chan Event
+	filter func(Event) bool
+}
+
+// Subscribe returns a channel receiving the events of an organisation which pass filter, or all of them if it is nil,
+// and a function which cancels the subscription, closing the channel. An orgID of uuid.Nil receives every organisation's events.
+// filter is called as each event is published, before the mutation making it returns.
+func (p *Publisher) Subscribe(orgID uuid.UUID, filter func(Event) bool) (<-chan Event, func()) {
+	sub := &subscription{events: make(chan Event, SubscriptionBuffer), filter: filter}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.subs == nil {
+		p.subs = map[uuid.UUID]map[*subscription]struct{}{}
+	}
+	if p.subs[orgID] == nil {
+		p.subs[orgID] = map[*subscription]struct{}{}
+	}
+	p.subs[orgID][sub] = struct{}{}
+	p.count.Add(1)
+
+	return sub.events, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.drop(orgID, sub)
+	}
+}
+
+// subscribed checks if anybody is listening, so mutations can skip building events otherwise
+func (p *Publisher) subscribed() bool {
+	return p != nil && p.count.Load() > 0
+}
+
+// publish delivers events in order, dropping any subscriber which has fallen too far behind
+func (p *Publisher) publish(events []Event) {
+	if !p.subscribed() || len(events) == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, e := range events {
+		p.deliver(e.Org(), e)
+		if e.Org() != uuid.Nil {
+			p.deliver(uuid.Nil, e)
+		}
+	}
+}
+
+// deliver sends an event to the subscribers of orgID, so must be called holding the lock
+func (p *Publisher) deliver(orgID uuid.UUID, e Event) {
+	for sub := range p.subs[orgID] {
+		if sub.filter != nil && !sub.filter(e) {
+			continue
+		}
+
+		select {
+		case sub.events <- e:
+		default:
+			p.drop(orgID, sub)
+		}
+	}
+}
+
+// drop removes a subscription and closes its channel, if it has not been already, so must be called holding the lock
+func (p *Publisher) drop(orgID uuid.UUID, sub *subscription) {
+	if _, found := p.subs[orgID][sub]; !found {
+		return
+	}
+
+	delete(p.subs[orgID], sub)
+	p.count.Add(-1)
+	if len(p.subs[orgID]) == 0 {
+		delete(p.subs, orgID)
+	}
+	close(sub.events)
+}
+
+// publishChanges publishes the difference between folders before and after a mutation which keeps their order,
+// as a FolderRenamed or FolderMoved for each folder whose path changed
+func (f *driver) publishChanges(before []Folder, after []Folder) {
+	if !f.events.subscribed() {
+		return
+	}
+
+	events := []Event{}
+	for i := range after {
+		var old, changed Folder = before[i], after[i]
+		if old.Paths == changed.Paths {
+			continue
+		}
+
+		if old.Name != changed.Name {
+			events = append(events, FolderRenamed{
+				Folder:  changed,
+				OldName: old.Name,
+				OldPath: old.Paths,
+				NewPath: changed.Paths,
+				Version: f.Version(changed.OrgId),
+			})
+		} else {
+			events = append(events, FolderMoved{
+				Folder:  changed,
+				OldPath: old.Paths,
+				NewPath: changed.Paths,
+				Version: f.Version(changed.OrgId),
+			})
+		}
+	}
+	f.events.publish(events)
+}
+
+// publishCreated publishes a new folder
+func (f *driver) publishCreated(created Folder) {
+	f.events.publish([]Event{FolderCreated{Folder: created, Version: f.Version(created.OrgId)}})
+}
+
+// publishDeleted publishes the folders removed by a mutation, which leaves the others in order
+func (f *driver) publishDeleted(before []Folder, after []Folder) {
+	if !f.events.subscribed() {
+		return
+	}
+
+	events := []Event{}
+	var j int = 0
+	for _, old := range before {
+		if j < len(after) && after[j] == old {
+			j++
+			continue
+		}
+		events = append(events, FolderDeleted{Folder: old, Version: f.Version(old.OrgId)})
+	}
+	f.events.publish(events)
+}
+
+func (f *driver) Subscribe(orgID uuid.UUID, filter func(Event) bool) (<-chan Event, func()) {
+	return f.events.Subscribe(orgID, filter)
+}
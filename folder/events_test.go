@@ -0,0 +1,153 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// drain reads the events already published to a channel, and whether it is still open
+func drain(events <-chan folder.Event) ([]folder.Event, bool) {
+	received := []folder.Event{}
+	for {
+		select {
+		case e, open := <-events:
+			if !open {
+				return received, false
+			}
+			received = append(received, e)
+		default:
+			return received, true
+		}
+	}
+}
+
+func Test_folder_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+	drivers := [...]struct {
+		testName  string
+		newDriver func([]folder.Folder) folder.IObservableDriver
+	}{
+		{testName: "Driver", newDriver: folder.NewDriver},
+		{testName: "Concurrent driver", newDriver: folder.NewConcurrentDriver},
+		{testName: "Sharded driver", newDriver: folder.NewShardedDriver},
+	}
+
+	for _, tt := range drivers {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			d := tt.newDriver([]folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+				{Name: "other", OrgId: otherOrgId, Paths: "other"},
+			})
+
+			all, cancelAll := d.Subscribe(validOrgId, nil)
+			defer cancelAll()
+			renames, cancelRenames := d.Subscribe(validOrgId, func(e folder.Event) bool {
+				_, renamed := e.(folder.FolderRenamed)
+				return renamed
+			})
+			other, cancelOther := d.Subscribe(otherOrgId, nil)
+			defer cancelOther()
+			every, cancelEvery := d.Subscribe(uuid.Nil, nil)
+			defer cancelEvery()
+
+			_, err := d.MoveFolderByPath(validOrgId, "alpha.bravo", "delta")
+			require.NoError(t, err)
+			_, err = d.RenameFolder(validOrgId, "delta.bravo", "echo")
+			require.NoError(t, err)
+			_, err = d.CreateFolder(validOrgId, "foxtrot", "delta")
+			require.NoError(t, err)
+			_, err = d.DeleteFolder(validOrgId, "delta.echo")
+			require.NoError(t, err)
+			_, err = d.DeleteFolder(validOrgId, "missing")
+			assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+
+			received, open := drain(all)
+			assert.True(t, open)
+			assert.Equal(t, []folder.Event{
+				folder.FolderMoved{
+					Folder:  folder.Folder{Name: "bravo", OrgId: validOrgId, Paths: "delta.bravo"},
+					OldPath: "alpha.bravo", NewPath: "delta.bravo", Version: 1,
+				},
+				folder.FolderMoved{
+					Folder:  folder.Folder{Name: "charlie", OrgId: validOrgId, Paths: "delta.bravo.charlie"},
+					OldPath: "alpha.bravo.charlie", NewPath: "delta.bravo.charlie", Version: 1,
+				},
+				folder.FolderRenamed{
+					Folder:  folder.Folder{Name: "echo", OrgId: validOrgId, Paths: "delta.echo"},
+					OldName: "bravo", OldPath: "delta.bravo", NewPath: "delta.echo", Version: 2,
+				},
+				folder.FolderMoved{
+					Folder:  folder.Folder{Name: "charlie", OrgId: validOrgId, Paths: "delta.echo.charlie"},
+					OldPath: "delta.bravo.charlie", NewPath: "delta.echo.charlie", Version: 2,
+				},
+				folder.FolderCreated{
+					Folder:  folder.Folder{Name: "foxtrot", OrgId: validOrgId, Paths: "delta.foxtrot"},
+					Version: 3,
+				},
+				folder.FolderDeleted{
+					Folder:  folder.Folder{Name: "echo", OrgId: validOrgId, Paths: "delta.echo"},
+					Version: 4,
+				},
+				folder.FolderDeleted{
+					Folder:  folder.Folder{Name: "charlie", OrgId: validOrgId, Paths: "delta.echo.charlie"},
+					Version: 4,
+				},
+			}, received)
+
+			received, _ = drain(renames)
+			assert.Len(t, received, 1, "Filters pick which events are received")
+
+			received, _ = drain(other)
+			assert.Empty(t, received, "Subscribers only receive the events of their organisation")
+
+			_, err = d.CreateFolder(otherOrgId, "golf", "")
+			require.NoError(t, err)
+			received, _ = drain(every)
+			assert.Len(t, received, 8, "Subscribers of uuid.Nil receive every organisation's events")
+
+			cancelRenames()
+			cancelRenames()
+			_, open = drain(renames)
+			assert.False(t, open, "Cancelling closes the channel, and may be repeated")
+		})
+	}
+}
+
+func Test_folder_Subscribe_SlowConsumer(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+
+	d := folder.NewShardedDriver([]folder.Folder{
+		{Name: "left", OrgId: validOrgId, Paths: "left"},
+		{Name: "right", OrgId: validOrgId, Paths: "right"},
+		{Name: "mover", OrgId: validOrgId, Paths: "left.mover"},
+	})
+
+	slow, cancelSlow := d.Subscribe(validOrgId, nil)
+	defer cancelSlow()
+
+	var from, to string = "left", "right"
+	for i := 0; i < folder.SubscriptionBuffer+1; i++ {
+		_, err := d.MoveFolderByPath(validOrgId, from+".mover", to)
+		require.NoError(t, err, "Mutations never wait on subscribers")
+		from, to = to, from
+	}
+
+	received, open := drain(slow)
+	assert.Len(t, received, folder.SubscriptionBuffer)
+	assert.False(t, open, "Subscribers which fall too far behind are dropped")
+}
@@ -12,21 +12,97 @@ type IDriver interface {
 	// where all possible children are returned without duplicates
 	GetAllChildFolders(orgID uuid.UUID, name string) ([]Folder, error)
 
-	// MoveFolder moves a folder to a new destination.
+	// MoveFolder moves a folder to a new destination, returning the folders left behind.
+	// Drivers which keep organisations apart, such as NewShardedDriver's, return only those of the organisation moved within.
 	// Assumption: Names are unique, as cannot distinguish between different paths.
 	// E.g. We have a, c.a and d. moveFolder("d", "a") is ambiguous, as we don't know
 	// which a to move to.
+	// Where names are repeated, which of the folders is moved, and beneath which, is up to the driver,
+	// though a move never crosses organisations, failing with ErrMoveToOtherOrg rather than doing so.
 	MoveFolder(name string, dst string) ([]Folder, error)
 }
 
+// IStatefulDriver is an IDriver which keeps the result of each mutation,
+// so later calls observe the folders left behind by earlier ones.
+// Unlike MoveFolder, these address folders by path within an organisation, which is unambiguous.
+// Each returns the folders left behind, which as with MoveFolder may be only those of orgID.
+type IStatefulDriver interface {
+	IDriver
+
+	// CreateFolder adds a folder called name beneath the folder at parent, or as a root folder if parent is empty.
+	CreateFolder(orgID uuid.UUID, name string, parent string) ([]Folder, error)
+
+	// MoveFolderByPath moves the folder at src, with its descendants, beneath the folder at dst,
+	// or to the root if dst is empty.
+	MoveFolderByPath(orgID uuid.UUID, src string, dst string) ([]Folder, error)
+
+	// RenameFolder renames the folder at path, updating the paths of its descendants.
+	RenameFolder(orgID uuid.UUID, path string, newName string) ([]Folder, error)
+
+	// DeleteFolder removes the folder at path along with its descendants.
+	DeleteFolder(orgID uuid.UUID, path string) ([]Folder, error)
+}
+
+// IVersionedDriver is an IStatefulDriver which versions each organisation's folders.
+// The version starts at zero and increases with every mutation which changes the organisation's folders,
+// so a caller can make a change only if nobody has changed them since it last read them.
+type IVersionedDriver interface {
+	IStatefulDriver
+
+	// Version returns the current version of an organisation's folders.
+	Version(orgID uuid.UUID) uint64
+
+	// GetVersionedFoldersByOrgID is GetFoldersByOrgID, also returning the version read.
+	GetVersionedFoldersByOrgID(orgID uuid.UUID) ([]Folder, uint64)
+
+	// GetVersionedChildFolders is GetAllChildFolders, also returning the version of orgID read.
+	GetVersionedChildFolders(orgID uuid.UUID, name string) ([]Folder, uint64, error)
+
+	// MoveFolderIfVersion is MoveFolder, failing with ErrVersionConflict unless the organisation
+	// of the folder called name is at version. It returns the version left behind.
+	MoveFolderIfVersion(version uint64, name string, dst string) ([]Folder, uint64, error)
+
+	// The remaining mutations are those of IStatefulDriver, failing with ErrVersionConflict
+	// unless orgID is at version. They return the version left behind.
+	CreateFolderIfVersion(orgID uuid.UUID, version uint64, name string, parent string) ([]Folder, uint64, error)
+	MoveFolderByPathIfVersion(orgID uuid.UUID, version uint64, src string, dst string) ([]Folder, uint64, error)
+	RenameFolderIfVersion(orgID uuid.UUID, version uint64, path string, newName string) ([]Folder, uint64, error)
+	DeleteFolderIfVersion(orgID uuid.UUID, version uint64, path string) ([]Folder, uint64, error)
+}
+
+// IObservableDriver is an IVersionedDriver which publishes an Event for each folder changed by a mutation.
+type IObservableDriver interface {
+	IVersionedDriver
+
+	// Subscribe returns a channel receiving the events of an organisation which pass filter, or all of them if it is nil,
+	// and a function which cancels the subscription. An orgID of uuid.Nil receives every organisation's events.
+	// Refer to Publisher for what happens to slow subscribers.
+	Subscribe(orgID uuid.UUID, filter func(Event) bool) (<-chan Event, func())
+}
+
+// Store is an IDriver backed by persistent storage, where moves are kept across calls and restarts
+type Store interface {
+	IDriver
+
+	// InsertFolders adds folders to the store.
+	InsertFolders(folders []Folder) error
+
+	// Close releases the underlying storage.
+	Close() error
+}
+
 // A driver which stores folders
 type driver struct {
-	folders []Folder
+	folders  []Folder
+	versions map[uuid.UUID]uint64
+	events   *Publisher
+	limits   Limits
 }
 
 // NewDriver creates a driver to execute utility functions
-func NewDriver(folders []Folder) IDriver {
+func NewDriver(folders []Folder) IObservableDriver {
 	return &driver{
 		folders: folders,
+		events:  &Publisher{},
 	}
 }
@@ -0,0 +1,150 @@
+package folder
+
+import (
+	"math/rand"
+
+	"github.com/gofrs/uuid"
+	"github.com/lucasepe/codename"
+)
+
+// GeneratorConfig describes the folders a Generator makes
+type GeneratorConfig struct {
+	// Orgs is how many organisations the roots are shared between, in turn. The first is DefaultOrgID.
+	Orgs int
+
+	// Roots is how many trees are made.
+	Roots int
+
+	// MinChildren and MaxChildren bound how many children each folder above MaxDepth has,
+	// chosen evenly between them.
+	MinChildren int
+	MaxChildren int
+
+	// MaxDepth is the depth of the deepest folders, where roots are at depth 1.
+	MaxDepth int
+
+	// CollisionRate is the chance a folder takes the name of another folder of its organisation,
+	// elsewhere in its tree or another. Otherwise names are unique within an organisation.
+	CollisionRate float64
+}
+
+// DefaultGeneratorConfig is the config GenerateData uses
+var DefaultGeneratorConfig = GeneratorConfig{
+	Orgs:        3,
+	Roots:       4,
+	MinChildren: 1,
+	MaxChildren: 4,
+	MaxDepth:    5,
+}
+
+// Generator makes random folders of a given shape, making the same folders every time for the same seed
+type Generator struct {
+	seed   int64
+	config GeneratorConfig
+}
+
+// NewGenerator returns a Generator of folders described by config, which must be valid
+func NewGenerator(seed int64, config GeneratorConfig) (*Generator, error) {
+	if config.Orgs < 1 || config.Roots < 0 || config.MinChildren < 0 || config.MaxChildren < config.MinChildren ||
+		config.MaxDepth < 1 || !(config.CollisionRate >= 0 && config.CollisionRate <= 1) {
+		return nil, ErrInvalidConfig
+	}
+	return &Generator{seed: seed, config: config}, nil
+}
+
+// Generate makes the folders, each tree following its root
+func (g *Generator) Generate() []Folder {
+	gen := &generation{
+		config:  g.config,
+		rng:     rand.New(rand.NewSource(g.seed)),
+		names:   map[uuid.UUID]*orgNames{},
+		folders: []Folder{},
+	}
+
+	orgs := make([]uuid.UUID, g.config.Orgs)
+	orgs[0] = uuid.FromStringOrNil(DefaultOrgID)
+	for i := 1; i < len(orgs); i++ {
+		orgs[i] = gen.orgID()
+	}
+
+	roots := map[uuid.UUID]map[string]bool{}
+	for i := 0; i < g.config.Roots; i++ {
+		orgID := orgs[i%len(orgs)]
+		if roots[orgID] == nil {
+			roots[orgID] = map[string]bool{}
+		}
+		gen.tree(orgID, "", gen.name(orgID, roots[orgID]), 1)
+	}
+	return gen.folders
+}
+
+// generation is the state of one run of a Generator
+type generation struct {
+	config  GeneratorConfig
+	rng     *rand.Rand
+	names   map[uuid.UUID]*orgNames
+	folders []Folder
+}
+
+// The names taken within an organisation, in the order they were first used
+type orgNames struct {
+	taken map[string]bool
+	order []string
+}
+
+// orgID makes a random version 4 UUID
+func (g *generation) orgID() uuid.UUID {
+	var id uuid.UUID
+	g.rng.Read(id[:])
+	id.SetVersion(uuid.V4)
+	id.SetVariant(uuid.VariantRFC4122)
+	return id
+}
+
+// tree adds a folder called name beneath parent, followed by its descendants
+func (g *generation) tree(orgID uuid.UUID, parent string, name string, depth int) {
+	var path string = JoinPath(parent, name)
+	g.folders = append(g.folders, Folder{Name: name, OrgId: orgID, Paths: path})
+	if depth >= g.config.MaxDepth {
+		return
+	}
+
+	var children int = g.config.MinChildren + g.rng.Intn(g.config.MaxChildren-g.config.MinChildren+1)
+	siblings := map[string]bool{}
+	for i := 0; i < children; i++ {
+		g.tree(orgID, path, g.name(orgID, siblings), depth+1)
+	}
+}
+
+// name picks a name for a folder which none of its siblings have,
+// reusing one taken elsewhere in the organisation at the config's CollisionRate
+func (g *generation) name(orgID uuid.UUID, siblings map[string]bool) string {
+	names := g.names[orgID]
+	if names == nil {
+		names = &orgNames{taken: map[string]bool{}}
+		g.names[orgID] = names
+	}
+
+	if len(names.order) > 0 && g.rng.Float64() < g.config.CollisionRate {
+		if name := names.order[g.rng.Intn(len(names.order))]; !siblings[name] {
+			siblings[name] = true
+			return name
+		}
+	}
+
+	// Codenames repeat once many are taken, so after a few tries add a token, longer with each try
+	for tries := 0; ; tries++ {
+		var tokenLength int = 0
+		if tries >= 3 {
+			tokenLength = tries
+		}
+
+		name := codename.Generate(g.rng, tokenLength)
+		if !names.taken[name] {
+			names.taken[name] = true
+			names.order = append(names.order, name)
+			siblings[name] = true
+			return name
+		}
+	}
+}
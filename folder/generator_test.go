@@ -0,0 +1,123 @@
+package folder_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_folder_Generator_Seed(t *testing.T) {
+	t.Parallel()
+
+	generate := func(seed int64) []byte {
+		g, err := folder.NewGenerator(seed, folder.DefaultGeneratorConfig)
+		require.NoError(t, err)
+		return folder.MarshalJson(g.Generate())
+	}
+
+	assert.Equal(t, generate(42), generate(42), "The same seed makes the same folders")
+	assert.NotEqual(t, generate(42), generate(43))
+
+	g, err := folder.NewGenerator(42, folder.DefaultGeneratorConfig)
+	require.NoError(t, err)
+	assert.Equal(t, folder.MarshalJson(g.Generate()), folder.MarshalJson(g.Generate()), "Each call makes the same folders")
+}
+
+func Test_folder_Generator_Config(t *testing.T) {
+	t.Parallel()
+
+	tests := [...]struct {
+		testName string
+		config   folder.GeneratorConfig
+	}{
+		{
+			testName: "Default",
+			config:   folder.DefaultGeneratorConfig,
+		},
+		{
+			testName: "Fixed fan-out",
+			config:   folder.GeneratorConfig{Orgs: 2, Roots: 3, MinChildren: 3, MaxChildren: 3, MaxDepth: 4},
+		},
+		{
+			testName: "Roots only",
+			config:   folder.GeneratorConfig{Orgs: 1, Roots: 5, MinChildren: 2, MaxChildren: 5, MaxDepth: 1},
+		},
+		{
+			testName: "More organisations than roots",
+			config:   folder.GeneratorConfig{Orgs: 4, Roots: 2, MinChildren: 0, MaxChildren: 2, MaxDepth: 6},
+		},
+		{
+			testName: "Names always collide",
+			config:   folder.GeneratorConfig{Orgs: 1, Roots: 2, MinChildren: 2, MaxChildren: 4, MaxDepth: 4, CollisionRate: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			g, err := folder.NewGenerator(7, tt.config)
+			require.NoError(t, err)
+			folders := g.Generate()
+			assert.Empty(t, folder.Validate(folders))
+
+			orgs := map[uuid.UUID]bool{}
+			roots := 0
+			children := map[string]int{}
+			names := map[string]int{}
+			for _, f := range folders {
+				orgs[f.OrgId] = true
+				names[f.OrgId.String()+"/"+f.Name]++
+
+				depth := strings.Count(f.Paths, ".") + 1
+				assert.LessOrEqual(t, depth, tt.config.MaxDepth)
+				if depth == 1 {
+					roots++
+				} else {
+					children[f.OrgId.String()+"/"+f.Paths[:strings.LastIndex(f.Paths, ".")]]++
+				}
+			}
+
+			assert.Equal(t, tt.config.Roots, roots)
+			assert.Len(t, orgs, min(tt.config.Orgs, tt.config.Roots))
+			assert.True(t, orgs[uuid.FromStringOrNil(folder.DefaultOrgID)])
+			for _, f := range folders {
+				if depth := strings.Count(f.Paths, ".") + 1; depth < tt.config.MaxDepth {
+					n := children[f.OrgId.String()+"/"+f.Paths]
+					assert.GreaterOrEqual(t, n, tt.config.MinChildren)
+					assert.LessOrEqual(t, n, tt.config.MaxChildren)
+				}
+			}
+
+			duplicates := 0
+			for _, n := range names {
+				duplicates += n - 1
+			}
+			if tt.config.CollisionRate == 0 {
+				assert.Zero(t, duplicates, "Names are unique within an organisation")
+			} else {
+				assert.Greater(t, duplicates, len(folders)/2)
+			}
+		})
+	}
+}
+
+func Test_folder_Generator_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, config := range []folder.GeneratorConfig{
+		{Orgs: 0, Roots: 1, MaxChildren: 1, MaxDepth: 1},
+		{Orgs: 1, Roots: -1, MaxChildren: 1, MaxDepth: 1},
+		{Orgs: 1, Roots: 1, MinChildren: 2, MaxChildren: 1, MaxDepth: 1},
+		{Orgs: 1, Roots: 1, MinChildren: -1, MaxChildren: 1, MaxDepth: 1},
+		{Orgs: 1, Roots: 1, MaxChildren: 1, MaxDepth: 0},
+		{Orgs: 1, Roots: 1, MaxChildren: 1, MaxDepth: 1, CollisionRate: 1.5},
+	} {
+		_, err := folder.NewGenerator(1, config)
+		assert.ErrorIs(t, err, folder.ErrInvalidConfig, "%+v", config)
+	}
+}
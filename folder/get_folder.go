@@ -1,10 +1,6 @@
 package folder
 
-import (
-	"errors"
-
-	"github.com/gofrs/uuid"
-)
+import "github.com/gofrs/uuid"
 
 func GetAllFolders() []Folder {
 	return GetSampleData()
@@ -18,13 +14,13 @@ func (f *driver) GetAllChildFolders(orgID uuid.UUID, name string) ([]Folder, err
 	var sameNamedFolders []Folder = findFoldersByName(&f.folders, name)
 
 	if len(sameNamedFolders) == 0 {
-		return []Folder{}, errors.New("folder does not exist")
+		return []Folder{}, ErrFolderNotFound
 	}
 
 	var parentFolders []Folder = findFoldersByOrgId(&sameNamedFolders, orgID)
 
 	if len(parentFolders) == 0 {
-		return []Folder{}, errors.New("folder does not exist in the specified organization")
+		return []Folder{}, ErrFolderNotInOrg
 	}
 
 	res := []Folder{}
@@ -125,6 +125,20 @@ func Test_folder_GetAllChildFolders(t *testing.T) {
 				{Name: "c2", OrgId: validOrgId, Paths: "fold.c1.c2"},
 			},
 		},
+		{
+			testName: "Folder whose name begins with the parent's, not returned",
+			orgID:    validOrgId,
+			name:     "fold",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "c1", OrgId: validOrgId, Paths: "fold.c1"},
+				{Name: "folder", OrgId: validOrgId, Paths: "folder"},
+				{Name: "c2", OrgId: validOrgId, Paths: "folder.c2"},
+			},
+			want: []folder.Folder{
+				{Name: "c1", OrgId: validOrgId, Paths: "fold.c1"},
+			},
+		},
 		{
 			testName: "Folder with parent and child, only child returned",
 			orgID:    validOrgId,
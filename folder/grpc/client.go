@@ -0,0 +1,175 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/folder/grpc/folderpb"
+	"github.com/gofrs/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultTimeout bounds each call made by a client's IDriver methods, unless its Timeout is changed
+const DefaultTimeout = 10 * time.Second
+
+// Client is an IDriver which calls a FolderService.
+// Errors sent by the server as driver errors are returned as them, so errors.Is works across the wire.
+type Client struct {
+	// Timeout bounds each call made by the IDriver methods, which take no context, or is zero for no deadline.
+	// Calls taking a context are bounded by it instead.
+	Timeout time.Duration
+
+	client folderpb.FolderServiceClient
+}
+
+// NewClient creates a client over a connection to a FolderService, whose calls time out after DefaultTimeout
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{Timeout: DefaultTimeout, client: folderpb.NewFolderServiceClient(conn)}
+}
+
+// context returns the context for a call made by an IDriver method, along with the function releasing it
+func (c *Client) context() (context.Context, context.CancelFunc) {
+	if c.Timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), c.Timeout)
+}
+
+// GetFoldersByOrgID implements IDriver, which has no way to report a failed call,
+// so returns no folders if the call fails. Use FoldersByOrgID to see the error.
+func (c *Client) GetFoldersByOrgID(orgID uuid.UUID) []folder.Folder {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	folders, err := c.FoldersByOrgID(ctx, orgID)
+	if err != nil {
+		return []folder.Folder{}
+	}
+	return folders
+}
+
+// FoldersByOrgID returns all folders that belong to an organisation
+func (c *Client) FoldersByOrgID(ctx context.Context, orgID uuid.UUID) ([]folder.Folder, error) {
+	res, err := c.client.GetFoldersByOrgID(ctx, &folderpb.GetFoldersByOrgIDRequest{OrgId: orgID.String()})
+	if err != nil {
+		return []folder.Folder{}, fromStatus(err)
+	}
+	return fromProto(res.GetFolders())
+}
+
+func (c *Client) GetAllChildFolders(orgID uuid.UUID, name string) ([]folder.Folder, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	res, err := c.client.GetAllChildFolders(ctx, &folderpb.GetAllChildFoldersRequest{OrgId: orgID.String(), Name: name})
+	if err != nil {
+		return []folder.Folder{}, fromStatus(err)
+	}
+	return fromProto(res.GetFolders())
+}
+
+// StreamChildFolders calls fn with each descendant of the folders called name as it arrives,
+// stopping early if fn returns an error
+func (c *Client) StreamChildFolders(ctx context.Context, orgID uuid.UUID, name string, fn func(folder.Folder) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.StreamChildFolders(ctx, &folderpb.GetAllChildFoldersRequest{OrgId: orgID.String(), Name: name})
+	if err != nil {
+		return fromStatus(err)
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fromStatus(err)
+		}
+
+		f, err := fromProtoFolder(msg)
+		if err != nil {
+			return err
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) MoveFolder(name string, dst string) ([]folder.Folder, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	res, err := c.client.MoveFolder(ctx, &folderpb.MoveFolderRequest{Name: name, Dst: dst})
+	if err != nil {
+		return []folder.Folder{}, fromStatus(err)
+	}
+	return fromProto(res.GetFolders())
+}
+
+// fromStatus turns a status back into the driver error it was sent as, if any, as named by its ErrorInfo
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != errorDomain {
+			continue
+		}
+
+		for _, s := range sentinels {
+			if info.GetReason() != s.reason {
+				continue
+			}
+			if st.Message() == s.err.Error() {
+				return s.err
+			}
+			return &remoteError{msg: st.Message(), err: s.err}
+		}
+	}
+	return err
+}
+
+// remoteError is a driver error sent by the server, keeping the message it was sent with
+type remoteError struct {
+	msg string
+	err error
+}
+
+func (e *remoteError) Error() string {
+	return e.msg
+}
+
+func (e *remoteError) Unwrap() error {
+	return e.err
+}
+
+func fromProto(folders []*folderpb.Folder) ([]folder.Folder, error) {
+	result := make([]folder.Folder, 0, len(folders))
+	for _, f := range folders {
+		converted, err := fromProtoFolder(f)
+		if err != nil {
+			return []folder.Folder{}, err
+		}
+		result = append(result, converted)
+	}
+	return result, nil
+}
+
+func fromProtoFolder(f *folderpb.Folder) (folder.Folder, error) {
+	orgID, err := uuid.FromString(f.GetOrgId())
+	if err != nil {
+		return folder.Folder{}, fmt.Errorf("invalid organisation ID %q from server: %w", f.GetOrgId(), err)
+	}
+	return folder.Folder{Name: f.GetName(), OrgId: orgID, Paths: f.GetPaths()}, nil
+}
@@ -0,0 +1,257 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: folderpb/folder.proto
+
+package folderpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FolderService_GetFoldersByOrgID_FullMethodName  = "/folder.v1.FolderService/GetFoldersByOrgID"
+	FolderService_GetAllChildFolders_FullMethodName = "/folder.v1.FolderService/GetAllChildFolders"
+	FolderService_StreamChildFolders_FullMethodName = "/folder.v1.FolderService/StreamChildFolders"
+	FolderService_MoveFolder_FullMethodName         = "/folder.v1.FolderService/MoveFolder"
+)
+
+// FolderServiceClient is the client API for FolderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FolderService exposes the operations of a folder driver.
+// Failures are reported with NOT_FOUND, ALREADY_EXISTS, FAILED_PRECONDITION for invalid moves,
+// and INVALID_ARGUMENT for invalid input, with the driver's error as the message.
+type FolderServiceClient interface {
+	// GetFoldersByOrgID returns all folders that belong to an organisation.
+	GetFoldersByOrgID(ctx context.Context, in *GetFoldersByOrgIDRequest, opts ...grpc.CallOption) (*GetFoldersByOrgIDResponse, error)
+	// GetAllChildFolders returns all descendants of the folders called name within an organisation.
+	GetAllChildFolders(ctx context.Context, in *GetAllChildFoldersRequest, opts ...grpc.CallOption) (*GetAllChildFoldersResponse, error)
+	// StreamChildFolders sends the same folders as GetAllChildFolders one at a time,
+	// so large subtrees need not fit in a single message.
+	StreamChildFolders(ctx context.Context, in *GetAllChildFoldersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Folder], error)
+	// MoveFolder moves the folder called name beneath the folder called dst, returning all folders.
+	MoveFolder(ctx context.Context, in *MoveFolderRequest, opts ...grpc.CallOption) (*MoveFolderResponse, error)
+}
+
+type folderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFolderServiceClient(cc grpc.ClientConnInterface) FolderServiceClient {
+	return &folderServiceClient{cc}
+}
+
+func (c *folderServiceClient) GetFoldersByOrgID(ctx context.Context, in *GetFoldersByOrgIDRequest, opts ...grpc.CallOption) (*GetFoldersByOrgIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFoldersByOrgIDResponse)
+	err := c.cc.Invoke(ctx, FolderService_GetFoldersByOrgID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *folderServiceClient) GetAllChildFolders(ctx context.Context, in *GetAllChildFoldersRequest, opts ...grpc.CallOption) (*GetAllChildFoldersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAllChildFoldersResponse)
+	err := c.cc.Invoke(ctx, FolderService_GetAllChildFolders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *folderServiceClient) StreamChildFolders(ctx context.Context, in *GetAllChildFoldersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Folder], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FolderService_ServiceDesc.Streams[0], FolderService_StreamChildFolders_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetAllChildFoldersRequest, Folder]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FolderService_StreamChildFoldersClient = grpc.ServerStreamingClient[Folder]
+
+func (c *folderServiceClient) MoveFolder(ctx context.Context, in *MoveFolderRequest, opts ...grpc.CallOption) (*MoveFolderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MoveFolderResponse)
+	err := c.cc.Invoke(ctx, FolderService_MoveFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FolderServiceServer is the server API for FolderService service.
+// All implementations must embed UnimplementedFolderServiceServer
+// for forward compatibility.
+//
+// FolderService exposes the operations of a folder driver.
+// Failures are reported with NOT_FOUND, ALREADY_EXISTS, FAILED_PRECONDITION for invalid moves,
+// and INVALID_ARGUMENT for invalid input, with the driver's error as the message.
+type FolderServiceServer interface {
+	// GetFoldersByOrgID returns all folders that belong to an organisation.
+	GetFoldersByOrgID(context.Context, *GetFoldersByOrgIDRequest) (*GetFoldersByOrgIDResponse, error)
+	// GetAllChildFolders returns all descendants of the folders called name within an organisation.
+	GetAllChildFolders(context.Context, *GetAllChildFoldersRequest) (*GetAllChildFoldersResponse, error)
+	// StreamChildFolders sends the same folders as GetAllChildFolders one at a time,
+	// so large subtrees need not fit in a single message.
+	StreamChildFolders(*GetAllChildFoldersRequest, grpc.ServerStreamingServer[Folder]) error
+	// MoveFolder moves the folder called name beneath the folder called dst, returning all folders.
+	MoveFolder(context.Context, *MoveFolderRequest) (*MoveFolderResponse, error)
+	mustEmbedUnimplementedFolderServiceServer()
+}
+
+// UnimplementedFolderServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFolderServiceServer struct{}
+
+func (UnimplementedFolderServiceServer) GetFoldersByOrgID(context.Context, *GetFoldersByOrgIDRequest) (*GetFoldersByOrgIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFoldersByOrgID not implemented")
+}
+func (UnimplementedFolderServiceServer) GetAllChildFolders(context.Context, *GetAllChildFoldersRequest) (*GetAllChildFoldersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllChildFolders not implemented")
+}
+func (UnimplementedFolderServiceServer) StreamChildFolders(*GetAllChildFoldersRequest, grpc.ServerStreamingServer[Folder]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamChildFolders not implemented")
+}
+func (UnimplementedFolderServiceServer) MoveFolder(context.Context, *MoveFolderRequest) (*MoveFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveFolder not implemented")
+}
+func (UnimplementedFolderServiceServer) mustEmbedUnimplementedFolderServiceServer() {}
+func (UnimplementedFolderServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeFolderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FolderServiceServer will
+// result in compilation errors.
+type UnsafeFolderServiceServer interface {
+	mustEmbedUnimplementedFolderServiceServer()
+}
+
+func RegisterFolderServiceServer(s grpc.ServiceRegistrar, srv FolderServiceServer) {
+	// If the following call pancis, it indicates UnimplementedFolderServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FolderService_ServiceDesc, srv)
+}
+
+func _FolderService_GetFoldersByOrgID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFoldersByOrgIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FolderServiceServer).GetFoldersByOrgID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FolderService_GetFoldersByOrgID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FolderServiceServer).GetFoldersByOrgID(ctx, req.(*GetFoldersByOrgIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FolderService_GetAllChildFolders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllChildFoldersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FolderServiceServer).GetAllChildFolders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FolderService_GetAllChildFolders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FolderServiceServer).GetAllChildFolders(ctx, req.(*GetAllChildFoldersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FolderService_StreamChildFolders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetAllChildFoldersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FolderServiceServer).StreamChildFolders(m, &grpc.GenericServerStream[GetAllChildFoldersRequest, Folder]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FolderService_StreamChildFoldersServer = grpc.ServerStreamingServer[Folder]
+
+func _FolderService_MoveFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FolderServiceServer).MoveFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FolderService_MoveFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FolderServiceServer).MoveFolder(ctx, req.(*MoveFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FolderService_ServiceDesc is the grpc.ServiceDesc for FolderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FolderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "folder.v1.FolderService",
+	HandlerType: (*FolderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetFoldersByOrgID",
+			Handler:    _FolderService_GetFoldersByOrgID_Handler,
+		},
+		{
+			MethodName: "GetAllChildFolders",
+			Handler:    _FolderService_GetAllChildFolders_Handler,
+		},
+		{
+			MethodName: "MoveFolder",
+			Handler:    _FolderService_MoveFolder_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamChildFolders",
+			Handler:       _FolderService_StreamChildFolders_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "folderpb/folder.proto",
+}
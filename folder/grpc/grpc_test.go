@@ -0,0 +1,258 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/folder/drivertest"
+	foldergrpc "github.com/georgechieng-sc/interns-2022/folder/grpc"
+	"github.com/georgechieng-sc/interns-2022/folder/grpc/folderpb"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+var sampleFolders = []folder.Folder{
+	{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+	{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+	{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+	{Name: "delta", OrgId: validOrgId, Paths: "alpha.delta"},
+	{Name: "echo", OrgId: validOrgId, Paths: "echo"},
+	{Name: "foxtrot", OrgId: otherOrgId, Paths: "foxtrot"},
+}
+
+// dial serves a fresh copy of the sample folders over an in-process listener, returning a connection to it
+func dial(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	return dialFolders(t, append([]folder.Folder{}, sampleFolders...))
+}
+
+// dialFolders serves a driver holding folders, returning a connection to it
+func dialFolders(t *testing.T, folders []folder.Folder) *grpc.ClientConn {
+	t.Helper()
+	return dialServer(t, foldergrpc.NewServer(folder.NewConcurrentDriver(folders)))
+}
+
+// dialServer serves a FolderService over an in-process listener, returning a connection to it
+func dialServer(t *testing.T, srv folderpb.FolderServiceServer) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	folderpb.RegisterFolderServiceServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func Test_grpc_Client(t *testing.T) {
+	t.Parallel()
+
+	var _ folder.IDriver = &foldergrpc.Client{}
+	c := foldergrpc.NewClient(dial(t))
+
+	assert.Equal(t, sampleFolders[:5], c.GetFoldersByOrgID(validOrgId))
+	assert.Equal(t, []folder.Folder{}, c.GetFoldersByOrgID(uuid.Must(uuid.NewV4())))
+
+	children, err := c.GetAllChildFolders(validOrgId, "alpha")
+	require.NoError(t, err)
+	assert.Equal(t, sampleFolders[1:4], children)
+
+	moved, err := c.MoveFolder("bravo", "echo")
+	require.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		sampleFolders[0],
+		{Name: "bravo", OrgId: validOrgId, Paths: "echo.bravo"},
+		{Name: "charlie", OrgId: validOrgId, Paths: "echo.bravo.charlie"},
+		sampleFolders[3],
+		sampleFolders[4],
+		sampleFolders[5],
+	}, moved)
+
+	children, err = c.GetAllChildFolders(validOrgId, "echo")
+	require.NoError(t, err)
+	assert.Equal(t, moved[1:3], children, "The server keeps the result of a move")
+}
+
+func Test_grpc_Errors(t *testing.T) {
+	t.Parallel()
+
+	c := foldergrpc.NewClient(dial(t))
+
+	tests := [...]struct {
+		testName string
+		call     func() error
+		want     error
+	}{
+		{
+			testName: "Folder not found",
+			call: func() error {
+				_, err := c.GetAllChildFolders(validOrgId, "zulu")
+				return err
+			},
+			want: folder.ErrFolderNotFound,
+		},
+		{
+			testName: "Folder in another organisation",
+			call: func() error {
+				_, err := c.GetAllChildFolders(validOrgId, "foxtrot")
+				return err
+			},
+			want: folder.ErrFolderNotInOrg,
+		},
+		{
+			testName: "Source not found",
+			call: func() error {
+				_, err := c.MoveFolder("zulu", "alpha")
+				return err
+			},
+			want: folder.ErrSourceNotFound,
+		},
+		{
+			testName: "Destination not found",
+			call: func() error {
+				_, err := c.MoveFolder("bravo", "zulu")
+				return err
+			},
+			want: folder.ErrDestinationNotFound,
+		},
+		{
+			testName: "Move to a child",
+			call: func() error {
+				_, err := c.MoveFolder("alpha", "charlie")
+				return err
+			},
+			want: folder.ErrMoveToChild,
+		},
+		{
+			testName: "Move to another organisation",
+			call: func() error {
+				_, err := c.MoveFolder("alpha", "foxtrot")
+				return err
+			},
+			want: folder.ErrMoveToOtherOrg,
+		},
+		{
+			testName: "Streamed folder not found",
+			call: func() error {
+				return c.StreamChildFolders(context.Background(), validOrgId, "zulu", func(folder.Folder) error { return nil })
+			},
+			want: folder.ErrFolderNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			err := tt.call()
+			assert.ErrorIs(t, err, tt.want, tt.testName)
+		})
+	}
+}
+
+// rewordedServer fails every call with a driver error whose message differs from the sentinel's
+type rewordedServer struct {
+	folderpb.UnimplementedFolderServiceServer
+}
+
+func (rewordedServer) MoveFolder(ctx context.Context, req *folderpb.MoveFolderRequest) (*folderpb.MoveFolderResponse, error) {
+	st, err := status.New(codes.NotFound, "no folder called "+req.GetName()).
+		WithDetails(&errdetails.ErrorInfo{Reason: "SOURCE_NOT_FOUND", Domain: "folders"})
+	if err != nil {
+		return nil, err
+	}
+	return nil, st.Err()
+}
+
+// GetAllChildFolders answers only once the call is abandoned
+func (rewordedServer) GetAllChildFolders(ctx context.Context, req *folderpb.GetAllChildFoldersRequest) (*folderpb.GetAllChildFoldersResponse, error) {
+	<-ctx.Done()
+	return nil, status.FromContextError(ctx.Err()).Err()
+}
+
+func Test_grpc_ErrorReasons(t *testing.T) {
+	t.Parallel()
+
+	// The sentinel is recognised by its reason, whatever the message says
+	c := foldergrpc.NewClient(dialServer(t, rewordedServer{}))
+	_, err := c.MoveFolder("zulu", "alpha")
+	assert.ErrorIs(t, err, folder.ErrSourceNotFound)
+	assert.EqualError(t, err, "no folder called zulu")
+}
+
+func Test_grpc_Timeout(t *testing.T) {
+	t.Parallel()
+
+	c := foldergrpc.NewClient(dialServer(t, rewordedServer{}))
+	c.Timeout = 50 * time.Millisecond
+
+	_, err := c.GetAllChildFolders(validOrgId, "alpha")
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
+
+func Test_grpc_StatusCodes(t *testing.T) {
+	t.Parallel()
+
+	c := folderpb.NewFolderServiceClient(dial(t))
+	ctx := context.Background()
+
+	_, err := c.GetFoldersByOrgID(ctx, &folderpb.GetFoldersByOrgIDRequest{OrgId: "nope"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = c.GetAllChildFolders(ctx, &folderpb.GetAllChildFoldersRequest{OrgId: validOrgId.String(), Name: "zulu"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = c.MoveFolder(ctx, &folderpb.MoveFolderRequest{Name: "alpha", Dst: "alpha"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func Test_grpc_StreamChildFolders(t *testing.T) {
+	t.Parallel()
+
+	c := foldergrpc.NewClient(dial(t))
+
+	streamed := []folder.Folder{}
+	err := c.StreamChildFolders(context.Background(), validOrgId, "alpha", func(f folder.Folder) error {
+		streamed = append(streamed, f)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, sampleFolders[1:4], streamed)
+
+	errStop := errors.New("stop")
+	streamed = []folder.Folder{}
+	err = c.StreamChildFolders(context.Background(), validOrgId, "alpha", func(f folder.Folder) error {
+		streamed = append(streamed, f)
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, sampleFolders[1:2], streamed, "Streaming stops once the callback fails")
+}
+
+func Test_grpc_Conformance(t *testing.T) {
+	t.Parallel()
+
+	drivertest.RunConformance(t, func(folders []folder.Folder) folder.IDriver {
+		return foldergrpc.NewClient(dialFolders(t, folders))
+	})
+}
@@ -0,0 +1,153 @@
+// Package grpc serves a folder driver over gRPC, and provides a client which is itself an IDriver.
+//
+// The service is defined in folderpb/folder.proto. After editing it, regenerate the code
+// with buf and the protoc-gen-go and protoc-gen-go-grpc plugins on the PATH:
+//
+//	go generate ./folder/grpc
+package grpc
+
+//go:generate buf generate
+
+import (
+	"context"
+	"errors"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/folder/grpc/folderpb"
+	"github.com/gofrs/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the ErrorInfo attached to each status sent for a driver error
+const errorDomain = "folders"
+
+// sentinels are the driver errors carried across the wire, along with the code they are sent as
+// and the ErrorInfo reason the client recognises them by, which must never change
+var sentinels = []struct {
+	err    error
+	code   codes.Code
+	reason string
+}{
+	{folder.ErrFolderNotFound, codes.NotFound, "FOLDER_NOT_FOUND"},
+	{folder.ErrFolderNotInOrg, codes.NotFound, "FOLDER_NOT_IN_ORG"},
+	{folder.ErrSourceNotFound, codes.NotFound, "SOURCE_NOT_FOUND"},
+	{folder.ErrDestinationNotFound, codes.NotFound, "DESTINATION_NOT_FOUND"},
+	{folder.ErrFolderExists, codes.AlreadyExists, "FOLDER_EXISTS"},
+	{folder.ErrMoveToSelf, codes.FailedPrecondition, "MOVE_TO_SELF"},
+	{folder.ErrMoveToChild, codes.FailedPrecondition, "MOVE_TO_CHILD"},
+	{folder.ErrMoveToOtherOrg, codes.FailedPrecondition, "MOVE_TO_OTHER_ORG"},
+	{folder.ErrInvalidName, codes.InvalidArgument, "INVALID_NAME"},
+	{folder.ErrInvalidPath, codes.InvalidArgument, "INVALID_PATH"},
+	{folder.ErrTooManyFolders, codes.ResourceExhausted, "TOO_MANY_FOLDERS"},
+	{folder.ErrTooDeep, codes.FailedPrecondition, "TOO_DEEP"},
+	{folder.ErrTooManyChildren, codes.FailedPrecondition, "TOO_MANY_CHILDREN"},
+	{folder.ErrPathTooLong, codes.FailedPrecondition, "PATH_TOO_LONG"},
+}
+
+// Server implements the FolderService over a driver
+type Server struct {
+	folderpb.UnimplementedFolderServiceServer
+
+	driver folder.IDriver
+}
+
+// NewServer creates a server over a driver, which must be safe for concurrent use,
+// such as one made by folder.NewConcurrentDriver or folder.NewShardedDriver, as calls are served in parallel.
+func NewServer(d folder.IDriver) *Server {
+	return &Server{driver: d}
+}
+
+// Register creates a server over a driver and registers it with s
+func Register(s *grpc.Server, d folder.IDriver) {
+	folderpb.RegisterFolderServiceServer(s, NewServer(d))
+}
+
+func (s *Server) GetFoldersByOrgID(ctx context.Context, req *folderpb.GetFoldersByOrgIDRequest) (*folderpb.GetFoldersByOrgIDResponse, error) {
+	orgID, err := parseOrg(req.GetOrgId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &folderpb.GetFoldersByOrgIDResponse{Folders: toProto(s.driver.GetFoldersByOrgID(orgID))}, nil
+}
+
+func (s *Server) GetAllChildFolders(ctx context.Context, req *folderpb.GetAllChildFoldersRequest) (*folderpb.GetAllChildFoldersResponse, error) {
+	children, err := s.children(req)
+	if err != nil {
+		return nil, err
+	}
+	return &folderpb.GetAllChildFoldersResponse{Folders: toProto(children)}, nil
+}
+
+func (s *Server) StreamChildFolders(req *folderpb.GetAllChildFoldersRequest, stream grpc.ServerStreamingServer[folderpb.Folder]) error {
+	children, err := s.children(req)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range children {
+		if err := stream.Send(toProtoFolder(f)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) MoveFolder(ctx context.Context, req *folderpb.MoveFolderRequest) (*folderpb.MoveFolderResponse, error) {
+	folders, err := s.driver.MoveFolder(req.GetName(), req.GetDst())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &folderpb.MoveFolderResponse{Folders: toProto(folders)}, nil
+}
+
+// children looks up the descendants asked for by a request, so the unary and streaming calls agree
+func (s *Server) children(req *folderpb.GetAllChildFoldersRequest) ([]folder.Folder, error) {
+	orgID, err := parseOrg(req.GetOrgId())
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := s.driver.GetAllChildFolders(orgID, req.GetName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return children, nil
+}
+
+func parseOrg(orgID string) (uuid.UUID, error) {
+	id, err := uuid.FromString(orgID)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid organisation ID %q", orgID)
+	}
+	return id, nil
+}
+
+// toStatus turns a driver error into a status, attaching an ErrorInfo whose reason names the sentinel
+func toStatus(err error) error {
+	for _, s := range sentinels {
+		if errors.Is(err, s.err) {
+			st, detailErr := status.New(s.code, err.Error()).WithDetails(&errdetails.ErrorInfo{Reason: s.reason, Domain: errorDomain})
+			if detailErr != nil {
+				return status.Error(s.code, err.Error())
+			}
+			return st.Err()
+		}
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func toProto(folders []folder.Folder) []*folderpb.Folder {
+	result := make([]*folderpb.Folder, 0, len(folders))
+	for _, f := range folders {
+		result = append(result, toProtoFolder(f))
+	}
+	return result
+}
+
+func toProtoFolder(f folder.Folder) *folderpb.Folder {
+	return &folderpb.Folder{Name: f.Name, OrgId: f.OrgId.String(), Paths: f.Paths}
+}
@@ -33,6 +33,70 @@ func findFoldersByOrgId(folders *[]Folder, orgId uuid.UUID) []Folder {
 
 // isChildFolder checks if the first folder is a parent, and the second is a child
 func isChildFolder(parent *Folder, child *Folder) bool {
-	return (child.OrgId == parent.OrgId &&
-		child.Paths != parent.Paths && strings.HasPrefix(child.Paths, parent.Paths))
+	return child.OrgId == parent.OrgId && IsDescendantPath(parent.Paths, child.Paths)
+}
+
+// findFolderByPath looks up the folder at path within an organisation
+func findFolderByPath(folders *[]Folder, orgId uuid.UUID, path string) (Folder, bool) {
+	for _, f := range *folders {
+		if f.OrgId == orgId && f.Paths == path {
+			return f, true
+		}
+	}
+	return Folder{}, false
+}
+
+// IsDescendantPath checks if child lies beneath parent, comparing whole labels
+func IsDescendantPath(parent string, child string) bool {
+	return strings.HasPrefix(child, parent+".")
+}
+
+// isValidName checks if a name can be used as a single ltree label
+func isValidName(name string) bool {
+	return name != "" && !strings.Contains(name, ".")
+}
+
+// ParentPath returns the path of a folder's parent, or empty for a root folder
+func ParentPath(path string) string {
+	i := strings.LastIndex(path, ".")
+	if i < 0 {
+		return ""
+	}
+	return path[:i]
+}
+
+// JoinPath appends a label to a parent path, which may be empty for a root folder
+func JoinPath(parent string, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// rebaseFolders copies folders, moving the folder at oldPath and its descendants to newPath
+func rebaseFolders(folders *[]Folder, orgId uuid.UUID, oldPath string, newPath string) []Folder {
+	result := make([]Folder, 0, len(*folders))
+	for _, f := range *folders {
+		if f.OrgId == orgId && (f.Paths == oldPath || IsDescendantPath(oldPath, f.Paths)) {
+			f.Paths = newPath + strings.TrimPrefix(f.Paths, oldPath)
+			f.Name = f.Paths[strings.LastIndex(f.Paths, ".")+1:]
+		}
+		result = append(result, f)
+	}
+	return result
+}
+
+// checkFolder checks a folder's path is made of valid labels, the last being its name
+func checkFolder(f *Folder) error {
+	for _, label := range strings.Split(f.Paths, ".") {
+		if label == "" {
+			return ErrInvalidPath
+		}
+	}
+
+	if f.Paths[strings.LastIndex(f.Paths, ".")+1:] != f.Name {
+		return ErrInvalidPath
+	}
+
+	return nil
 }
@@ -0,0 +1,266 @@
+// Package http serves a folder driver as a JSON REST API.
+//
+// Folders are addressed by their path within an organisation:
+//
+//	GET    /orgs/{org}/folders                  list the folders of an organisation
+//	POST   /orgs/{org}/folders                  create a folder, from {"name", "parent"}
+//	GET    /orgs/{org}/folders/{path}/children  list the descendants of a folder
+//	POST   /orgs/{org}/folders/{path}/move      move a folder beneath {"dst"}, or to the root if it is empty
+//	POST   /orgs/{org}/folders/{path}/rename    rename a folder to {"name"}
+//	DELETE /orgs/{org}/folders/{path}           delete a folder along with its descendants
+//
+// Mutations respond with the organisation's folders left behind. Failures respond with an Error body.
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+)
+
+// maxBodySize bounds the size of request bodies, which only ever hold a few names
+const maxBodySize = 1 << 16
+
+// Error is the body of a failed request
+type Error struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// CreateRequest is the body of a create request
+type CreateRequest struct {
+	Name   string `json:"name"`
+	Parent string `json:"parent"`
+}
+
+// MoveRequest is the body of a move request
+type MoveRequest struct {
+	Dst string `json:"dst"`
+}
+
+// RenameRequest is the body of a rename request
+type RenameRequest struct {
+	Name string `json:"name"`
+}
+
+// Server is an http.Handler serving the folders of a driver
+type Server struct {
+	// OnChange, if set, is called with the folders each successful mutation leaves behind, such as to save them.
+	// These are all folders, unless the driver returns only those of the organisation changed.
+	// The mutation fails with a 500 if it returns an error, though the driver has already applied it.
+	// While it is set, mutations run one at a time, so changes reach it in the order they were made.
+	OnChange func(folders []folder.Folder) error
+
+	changes sync.Mutex
+	driver  folder.IStatefulDriver
+	mux     *http.ServeMux
+}
+
+// NewServer creates a server over a driver, which must be safe for concurrent use,
+// such as one made by folder.NewConcurrentDriver or folder.NewShardedDriver, as requests are served in parallel.
+func NewServer(d folder.IStatefulDriver) *Server {
+	s := &Server{driver: d, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /orgs/{org}/folders", s.list)
+	s.mux.HandleFunc("POST /orgs/{org}/folders", s.create)
+	s.mux.HandleFunc("GET /orgs/{org}/folders/{path}/children", s.children)
+	s.mux.HandleFunc("POST /orgs/{org}/folders/{path}/move", s.move)
+	s.mux.HandleFunc("POST /orgs/{org}/folders/{path}/rename", s.rename)
+	s.mux.HandleFunc("DELETE /orgs/{org}/folders/{path}", s.delete)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	orgID, ok := parseOrg(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.driver.GetFoldersByOrgID(orgID))
+}
+
+func (s *Server) children(w http.ResponseWriter, r *http.Request) {
+	orgID, ok := parseOrg(w, r)
+	if !ok {
+		return
+	}
+	var path string = r.PathValue("path")
+
+	var found bool = false
+	children := []folder.Folder{}
+	for _, f := range s.driver.GetFoldersByOrgID(orgID) {
+		if f.Paths == path {
+			found = true
+		} else if folder.IsDescendantPath(path, f.Paths) {
+			children = append(children, f)
+		}
+	}
+
+	if !found {
+		writeError(w, fmt.Errorf("%w: %q", folder.ErrFolderNotFound, path))
+		return
+	}
+	writeJSON(w, http.StatusOK, children)
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	orgID, ok := parseOrg(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mutate(w, orgID, http.StatusCreated, func() ([]folder.Folder, error) {
+		return s.driver.CreateFolder(orgID, req.Name, req.Parent)
+	})
+}
+
+func (s *Server) move(w http.ResponseWriter, r *http.Request) {
+	orgID, ok := parseOrg(w, r)
+	if !ok {
+		return
+	}
+
+	var req MoveRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mutate(w, orgID, http.StatusOK, func() ([]folder.Folder, error) {
+		return s.driver.MoveFolderByPath(orgID, r.PathValue("path"), req.Dst)
+	})
+}
+
+func (s *Server) rename(w http.ResponseWriter, r *http.Request) {
+	orgID, ok := parseOrg(w, r)
+	if !ok {
+		return
+	}
+
+	var req RenameRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mutate(w, orgID, http.StatusOK, func() ([]folder.Folder, error) {
+		return s.driver.RenameFolder(orgID, r.PathValue("path"), req.Name)
+	})
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request) {
+	orgID, ok := parseOrg(w, r)
+	if !ok {
+		return
+	}
+
+	s.mutate(w, orgID, http.StatusOK, func() ([]folder.Folder, error) {
+		return s.driver.DeleteFolder(orgID, r.PathValue("path"))
+	})
+}
+
+// mutate runs a mutation, responding with the organisation's folders it leaves behind
+func (s *Server) mutate(w http.ResponseWriter, orgID uuid.UUID, status int, fn func() ([]folder.Folder, error)) {
+	if s.OnChange != nil {
+		s.changes.Lock()
+		defer s.changes.Unlock()
+	}
+
+	folders, err := fn()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if s.OnChange != nil {
+		if err := s.OnChange(folders); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	writeJSON(w, status, s.driver.GetFoldersByOrgID(orgID))
+}
+
+// parseOrg reads the organisation ID from the request path, responding with a 400 if it is invalid
+func parseOrg(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	orgID, err := uuid.FromString(r.PathValue("org"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, Error{
+			Status:  http.StatusBadRequest,
+			Code:    "invalid_org",
+			Message: fmt.Sprintf("invalid organisation ID %q", r.PathValue("org")),
+		})
+		return uuid.Nil, false
+	}
+	return orgID, true
+}
+
+// readJSON decodes the request body into v, responding with a 400 if it cannot
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, Error{
+			Status:  http.StatusBadRequest,
+			Code:    "invalid_body",
+			Message: err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// writeError responds with the status and code matching a driver error
+func writeError(w http.ResponseWriter, err error) {
+	var status int = http.StatusInternalServerError
+	var code string = "internal"
+
+	switch {
+	case errors.Is(err, folder.ErrFolderNotFound),
+		errors.Is(err, folder.ErrFolderNotInOrg),
+		errors.Is(err, folder.ErrSourceNotFound),
+		errors.Is(err, folder.ErrDestinationNotFound):
+		status, code = http.StatusNotFound, "not_found"
+	case errors.Is(err, folder.ErrFolderExists):
+		status, code = http.StatusConflict, "conflict"
+	case errors.Is(err, folder.ErrVersionConflict):
+		status, code = http.StatusConflict, "version_conflict"
+	case errors.Is(err, folder.ErrMoveToSelf),
+		errors.Is(err, folder.ErrMoveToChild),
+		errors.Is(err, folder.ErrMoveToOtherOrg):
+		status, code = http.StatusUnprocessableEntity, "invalid_move"
+	case errors.Is(err, folder.ErrTooManyFolders),
+		errors.Is(err, folder.ErrTooDeep),
+		errors.Is(err, folder.ErrTooManyChildren),
+		errors.Is(err, folder.ErrPathTooLong):
+		status, code = http.StatusUnprocessableEntity, "limit_exceeded"
+	case errors.Is(err, folder.ErrInvalidName),
+		errors.Is(err, folder.ErrInvalidPath):
+		status, code = http.StatusBadRequest, "invalid_input"
+	}
+
+	writeJSON(w, status, Error{Status: status, Code: code, Message: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
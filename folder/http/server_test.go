@@ -0,0 +1,389 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	folderhttp "github.com/georgechieng-sc/interns-2022/folder/http"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+var sampleFolders = []folder.Folder{
+	{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+	{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+	{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+	{Name: "delta", OrgId: validOrgId, Paths: "alpha.delta"},
+	{Name: "echo", OrgId: validOrgId, Paths: "echo"},
+	{Name: "foxtrot", OrgId: otherOrgId, Paths: "foxtrot"},
+}
+
+// do sends a request to a server, checking the response is JSON
+func do(t *testing.T, s *folderhttp.Server, method string, target string, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	return w
+}
+
+// newServer creates a server over a fresh copy of the sample folders
+func newServer() *folderhttp.Server {
+	return folderhttp.NewServer(folder.NewConcurrentDriver(append([]folder.Folder{}, sampleFolders...)))
+}
+
+func Test_http_Success(t *testing.T) {
+	t.Parallel()
+
+	var org string = "/orgs/" + validOrgId.String()
+
+	tests := [...]struct {
+		testName string
+		method   string
+		target   string
+		body     string
+		status   int
+		want     []folder.Folder
+	}{
+		{
+			testName: "List the folders of an organisation",
+			method:   http.MethodGet,
+			target:   "/orgs/" + otherOrgId.String() + "/folders",
+			status:   http.StatusOK,
+			want:     sampleFolders[5:],
+		},
+		{
+			testName: "List the folders of an empty organisation",
+			method:   http.MethodGet,
+			target:   "/orgs/" + uuid.Must(uuid.NewV4()).String() + "/folders",
+			status:   http.StatusOK,
+			want:     []folder.Folder{},
+		},
+		{
+			testName: "Children of a folder by path",
+			method:   http.MethodGet,
+			target:   org + "/folders/alpha.bravo/children",
+			status:   http.StatusOK,
+			want:     sampleFolders[2:3],
+		},
+		{
+			testName: "Create a folder",
+			method:   http.MethodPost,
+			target:   org + "/folders",
+			body:     `{"name": "golf", "parent": "echo"}`,
+			status:   http.StatusCreated,
+			want: append(append([]folder.Folder{}, sampleFolders[:5]...),
+				folder.Folder{Name: "golf", OrgId: validOrgId, Paths: "echo.golf"}),
+		},
+		{
+			testName: "Move a folder",
+			method:   http.MethodPost,
+			target:   org + "/folders/alpha.bravo/move",
+			body:     `{"dst": "echo"}`,
+			status:   http.StatusOK,
+			want: []folder.Folder{
+				sampleFolders[0],
+				{Name: "bravo", OrgId: validOrgId, Paths: "echo.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "echo.bravo.charlie"},
+				sampleFolders[3],
+				sampleFolders[4],
+			},
+		},
+		{
+			testName: "Rename a folder",
+			method:   http.MethodPost,
+			target:   org + "/folders/alpha.delta/rename",
+			body:     `{"name": "hotel"}`,
+			status:   http.StatusOK,
+			want: []folder.Folder{
+				sampleFolders[0],
+				sampleFolders[1],
+				sampleFolders[2],
+				{Name: "hotel", OrgId: validOrgId, Paths: "alpha.hotel"},
+				sampleFolders[4],
+			},
+		},
+		{
+			testName: "Delete a folder",
+			method:   http.MethodDelete,
+			target:   org + "/folders/alpha.bravo",
+			status:   http.StatusOK,
+			want:     []folder.Folder{sampleFolders[0], sampleFolders[3], sampleFolders[4]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			w := do(t, newServer(), tt.method, tt.target, tt.body)
+			require.Equal(t, tt.status, w.Code, w.Body.String())
+
+			var got []folder.Folder
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.want, got, tt.testName)
+		})
+	}
+}
+
+func Test_http_Errors(t *testing.T) {
+	t.Parallel()
+
+	var org string = "/orgs/" + validOrgId.String()
+
+	tests := [...]struct {
+		testName string
+		method   string
+		target   string
+		body     string
+		status   int
+		code     string
+	}{
+		{
+			testName: "Invalid organisation ID",
+			method:   http.MethodGet,
+			target:   "/orgs/nope/folders",
+			status:   http.StatusBadRequest,
+			code:     "invalid_org",
+		},
+		{
+			testName: "Children of a missing folder",
+			method:   http.MethodGet,
+			target:   org + "/folders/zulu/children",
+			status:   http.StatusNotFound,
+			code:     "not_found",
+		},
+		{
+			testName: "Children of a folder in another organisation",
+			method:   http.MethodGet,
+			target:   org + "/folders/foxtrot/children",
+			status:   http.StatusNotFound,
+			code:     "not_found",
+		},
+		{
+			testName: "Create beneath a missing parent",
+			method:   http.MethodPost,
+			target:   org + "/folders",
+			body:     `{"name": "golf", "parent": "zulu"}`,
+			status:   http.StatusNotFound,
+			code:     "not_found",
+		},
+		{
+			testName: "Create an existing folder",
+			method:   http.MethodPost,
+			target:   org + "/folders",
+			body:     `{"name": "bravo", "parent": "alpha"}`,
+			status:   http.StatusConflict,
+			code:     "conflict",
+		},
+		{
+			testName: "Create with an invalid name",
+			method:   http.MethodPost,
+			target:   org + "/folders",
+			body:     `{"name": "go.lf"}`,
+			status:   http.StatusBadRequest,
+			code:     "invalid_input",
+		},
+		{
+			testName: "Malformed body",
+			method:   http.MethodPost,
+			target:   org + "/folders",
+			body:     `{"name": `,
+			status:   http.StatusBadRequest,
+			code:     "invalid_body",
+		},
+		{
+			testName: "Unknown field in body",
+			method:   http.MethodPost,
+			target:   org + "/folders/alpha/move",
+			body:     `{"destination": "echo"}`,
+			status:   http.StatusBadRequest,
+			code:     "invalid_body",
+		},
+		{
+			testName: "Move to a child",
+			method:   http.MethodPost,
+			target:   org + "/folders/alpha/move",
+			body:     `{"dst": "alpha.bravo"}`,
+			status:   http.StatusUnprocessableEntity,
+			code:     "invalid_move",
+		},
+		{
+			testName: "Move to itself",
+			method:   http.MethodPost,
+			target:   org + "/folders/alpha/move",
+			body:     `{"dst": "alpha"}`,
+			status:   http.StatusUnprocessableEntity,
+			code:     "invalid_move",
+		},
+		{
+			testName: "Move to a missing destination",
+			method:   http.MethodPost,
+			target:   org + "/folders/alpha/move",
+			body:     `{"dst": "zulu"}`,
+			status:   http.StatusNotFound,
+			code:     "not_found",
+		},
+		{
+			testName: "Rename onto an existing folder",
+			method:   http.MethodPost,
+			target:   org + "/folders/alpha.delta/rename",
+			body:     `{"name": "bravo"}`,
+			status:   http.StatusConflict,
+			code:     "conflict",
+		},
+		{
+			testName: "Delete a missing folder",
+			method:   http.MethodDelete,
+			target:   org + "/folders/zulu",
+			status:   http.StatusNotFound,
+			code:     "not_found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			w := do(t, newServer(), tt.method, tt.target, tt.body)
+			require.Equal(t, tt.status, w.Code, w.Body.String())
+
+			var got folderhttp.Error
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.status, got.Status)
+			assert.Equal(t, tt.code, got.Code, tt.testName)
+			assert.NotEmpty(t, got.Message)
+		})
+	}
+}
+
+func Test_http_LimitExceeded(t *testing.T) {
+	t.Parallel()
+
+	d, err := folder.NewLimitedDriver(folder.NewConcurrentDriver, append([]folder.Folder{}, sampleFolders...), folder.Limits{MaxDepth: 3})
+	require.NoError(t, err)
+
+	w := do(t, folderhttp.NewServer(d), http.MethodPost, "/orgs/"+validOrgId.String()+"/folders/alpha.bravo/move", `{"dst": "alpha.delta"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var got folderhttp.Error
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "limit_exceeded", got.Code)
+	assert.Contains(t, got.Message, "MaxDepth is 3")
+}
+
+// conflictingDriver fails every create as though another writer had changed the organisation first
+type conflictingDriver struct {
+	folder.IStatefulDriver
+}
+
+func (conflictingDriver) CreateFolder(orgID uuid.UUID, name string, parent string) ([]folder.Folder, error) {
+	return []folder.Folder{}, folder.ErrVersionConflict
+}
+
+func Test_http_VersionConflict(t *testing.T) {
+	t.Parallel()
+
+	s := folderhttp.NewServer(conflictingDriver{folder.NewConcurrentDriver(nil)})
+	w := do(t, s, http.MethodPost, "/orgs/"+validOrgId.String()+"/folders", `{"name": "golf"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var got folderhttp.Error
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "version_conflict", got.Code)
+}
+
+// barrierDriver holds each read until as many as the barrier awaits are in progress at once
+type barrierDriver struct {
+	folder.IStatefulDriver
+	barrier *sync.WaitGroup
+}
+
+func (b barrierDriver) GetFoldersByOrgID(orgID uuid.UUID) []folder.Folder {
+	b.barrier.Done()
+	b.barrier.Wait()
+	return b.IStatefulDriver.GetFoldersByOrgID(orgID)
+}
+
+func Test_http_ParallelRequests(t *testing.T) {
+	t.Parallel()
+
+	// Two reads only get past the barrier if the server lets them run at the same time
+	var barrier sync.WaitGroup
+	barrier.Add(2)
+	s := folderhttp.NewServer(barrierDriver{folder.NewConcurrentDriver(sampleFolders), &barrier})
+
+	done := make(chan int, 2)
+	for range 2 {
+		go func() {
+			r := httptest.NewRequest(http.MethodGet, "/orgs/"+validOrgId.String()+"/folders", nil)
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, r)
+			done <- w.Code
+		}()
+	}
+
+	for range 2 {
+		select {
+		case code := <-done:
+			assert.Equal(t, http.StatusOK, code)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Requests were served one at a time")
+		}
+	}
+}
+
+func Test_http_OnChange(t *testing.T) {
+	t.Parallel()
+
+	s := newServer()
+	var saved []folder.Folder
+	s.OnChange = func(folders []folder.Folder) error {
+		saved = folders
+		return nil
+	}
+
+	w := do(t, s, http.MethodGet, "/orgs/"+validOrgId.String()+"/folders", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, saved, "Reads do not change the folders")
+
+	w = do(t, s, http.MethodDelete, "/orgs/"+validOrgId.String()+"/folders/alpha", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []folder.Folder{sampleFolders[4], sampleFolders[5]}, saved, "All organisations are passed on")
+
+	s.OnChange = func(folders []folder.Folder) error {
+		return errors.New("disk full")
+	}
+	w = do(t, s, http.MethodDelete, "/orgs/"+validOrgId.String()+"/folders/echo", "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func Test_http_OverServer(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(newServer())
+	defer ts.Close()
+
+	res, err := ts.Client().Post(ts.URL+"/orgs/"+validOrgId.String()+"/folders", "application/json", strings.NewReader(`{"name": "golf"}`))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+	res, err = ts.Client().Get(ts.URL + "/orgs/" + validOrgId.String() + "/folders/golf/children")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	res, err = ts.Client().Get(ts.URL + "/orgs/" + validOrgId.String() + "/nothing")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
@@ -0,0 +1,173 @@
+package folder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// Limits bound the shape of each organisation's folders, where zero leaves a limit unset.
+// Depth counts the labels of a path as GeneratorConfig does, so roots are at depth 1,
+// and path length counts its bytes, dots included. Root folders are not children, so MaxChildren leaves them be.
+type Limits struct {
+	MaxFolders    int
+	MaxDepth      int
+	MaxChildren   int
+	MaxPathLength int
+}
+
+// limited is a driver which can enforce limits
+type limited interface {
+	setLimits(limits Limits)
+	getLimits() Limits
+}
+
+// NewLimitedDriver creates a driver with newDriver, one of NewDriver, NewConcurrentDriver or NewShardedDriver,
+// whose mutations fail rather than take an organisation beyond limits. It fails if folders already exceed them,
+// or with ErrLimitsUnsupported if newDriver makes any other driver.
+func NewLimitedDriver(newDriver func([]Folder) IObservableDriver, folders []Folder, limits Limits) (IObservableDriver, error) {
+	if err := CheckLimits(folders, limits); err != nil {
+		return nil, err
+	}
+
+	d := newDriver(folders)
+	l, ok := d.(limited)
+	if !ok {
+		return nil, ErrLimitsUnsupported
+	}
+	l.setLimits(limits)
+	return d, nil
+}
+
+// LimitsOf returns the limits d enforces, which are unset unless d was made by NewLimitedDriver.
+// Imports check the folders they would add against them before adding any.
+func LimitsOf(d IDriver) Limits {
+	if l, ok := d.(limited); ok {
+		return l.getLimits()
+	}
+	return Limits{}
+}
+
+// CheckLimits checks folders keep within limits, so they can be imported into a driver enforcing them.
+// It returns the first limit exceeded, or ErrInvalidLimits if a limit is negative.
+func CheckLimits(folders []Folder, limits Limits) error {
+	if limits.MaxFolders < 0 || limits.MaxDepth < 0 || limits.MaxChildren < 0 || limits.MaxPathLength < 0 {
+		return ErrInvalidLimits
+	}
+
+	counts := map[uuid.UUID]int{}
+	children := map[uuid.UUID]map[string]int{}
+	for _, f := range folders {
+		if err := limits.checkPath(f.Paths); err != nil {
+			return err
+		}
+
+		counts[f.OrgId]++
+		if limits.MaxFolders > 0 && counts[f.OrgId] > limits.MaxFolders {
+			return fmt.Errorf("%w: MaxFolders is %d, exceeded by organisation %s", ErrTooManyFolders, limits.MaxFolders, f.OrgId)
+		}
+
+		if parent := ParentPath(f.Paths); parent != "" && limits.MaxChildren > 0 {
+			if children[f.OrgId] == nil {
+				children[f.OrgId] = map[string]int{}
+			}
+			children[f.OrgId][parent]++
+			if children[f.OrgId][parent] > limits.MaxChildren {
+				return fmt.Errorf("%w: MaxChildren is %d, exceeded by %q", ErrTooManyChildren, limits.MaxChildren, parent)
+			}
+		}
+	}
+	return nil
+}
+
+// depth counts the labels of a path
+func depth(path string) int {
+	return strings.Count(path, ".") + 1
+}
+
+// checkPath checks the depth and length of a folder's path
+func (l Limits) checkPath(path string) error {
+	if l.MaxDepth > 0 && depth(path) > l.MaxDepth {
+		return fmt.Errorf("%w: MaxDepth is %d, exceeded by %q", ErrTooDeep, l.MaxDepth, path)
+	}
+	if l.MaxPathLength > 0 && len(path) > l.MaxPathLength {
+		return fmt.Errorf("%w: MaxPathLength is %d, exceeded by %q", ErrPathTooLong, l.MaxPathLength, path)
+	}
+	return nil
+}
+
+func (f *driver) setLimits(limits Limits) {
+	f.limits = limits
+}
+
+func (f *driver) getLimits() Limits {
+	return f.limits
+}
+
+// checkChildren checks the folder at parent can take another child, other than the folder at except
+func (f *driver) checkChildren(orgID uuid.UUID, parent string, except string) error {
+	if f.limits.MaxChildren == 0 || parent == "" {
+		return nil
+	}
+
+	var count int = 0
+	for _, c := range f.folders {
+		if c.OrgId == orgID && c.Paths != except && ParentPath(c.Paths) == parent {
+			count++
+		}
+	}
+	if count >= f.limits.MaxChildren {
+		return fmt.Errorf("%w: MaxChildren is %d, reached by %q", ErrTooManyChildren, f.limits.MaxChildren, parent)
+	}
+	return nil
+}
+
+// checkCreate checks a folder can be created at path
+func (f *driver) checkCreate(orgID uuid.UUID, path string) error {
+	if f.limits == (Limits{}) {
+		return nil
+	}
+
+	if err := f.limits.checkPath(path); err != nil {
+		return err
+	}
+	if err := f.checkChildren(orgID, ParentPath(path), ""); err != nil {
+		return err
+	}
+	if f.limits.MaxFolders > 0 && len(findFoldersByOrgId(&f.folders, orgID)) >= f.limits.MaxFolders {
+		return fmt.Errorf("%w: MaxFolders is %d, reached by organisation %s", ErrTooManyFolders, f.limits.MaxFolders, orgID)
+	}
+	return nil
+}
+
+// checkMove checks the folder src can be moved or renamed to newPath, taking its descendants with it
+func (f *driver) checkMove(src Folder, newPath string) error {
+	if f.limits == (Limits{}) {
+		return nil
+	}
+
+	// The deepest and the longest descendants are the ones which could exceed a limit once moved
+	var deepest, longest string = newPath, newPath
+	for _, c := range f.folders {
+		if c.OrgId != src.OrgId || !IsDescendantPath(src.Paths, c.Paths) {
+			continue
+		}
+
+		var moved string = newPath + strings.TrimPrefix(c.Paths, src.Paths)
+		if depth(moved) > depth(deepest) {
+			deepest = moved
+		}
+		if len(moved) > len(longest) {
+			longest = moved
+		}
+	}
+
+	if err := f.limits.checkPath(deepest); err != nil {
+		return err
+	}
+	if err := f.limits.checkPath(longest); err != nil {
+		return err
+	}
+	return f.checkChildren(src.OrgId, ParentPath(newPath), src.Paths)
+}
@@ -0,0 +1,261 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_folder_CheckLimits(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+	folders := []folder.Folder{
+		{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+		{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+		{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+		{Name: "delta", OrgId: validOrgId, Paths: "alpha.delta"},
+		{Name: "echo", OrgId: validOrgId, Paths: "echo"},
+		{Name: "foxtrot", OrgId: otherOrgId, Paths: "foxtrot"},
+	}
+
+	tests := [...]struct {
+		testName string
+		limits   folder.Limits
+		err      error
+	}{
+		{testName: "No limits", limits: folder.Limits{}},
+		{testName: "Exactly at every limit", limits: folder.Limits{MaxFolders: 5, MaxDepth: 3, MaxChildren: 2, MaxPathLength: 19}},
+		{testName: "Too many folders in an org", limits: folder.Limits{MaxFolders: 4}, err: folder.ErrTooManyFolders},
+		{testName: "Too deep", limits: folder.Limits{MaxDepth: 2}, err: folder.ErrTooDeep},
+		{testName: "Too many children", limits: folder.Limits{MaxChildren: 1}, err: folder.ErrTooManyChildren},
+		{testName: "Path too long", limits: folder.Limits{MaxPathLength: 18}, err: folder.ErrPathTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			err := folder.CheckLimits(folders, tt.limits)
+			if tt.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.err)
+
+			_, err = folder.NewLimitedDriver(folder.NewDriver, folders, tt.limits)
+			assert.ErrorIs(t, err, tt.err, "Drivers cannot be made over folders exceeding their limits")
+		})
+	}
+}
+
+func Test_folder_NewLimitedDriver_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := folder.NewLimitedDriver(folder.NewDriver, []folder.Folder{}, folder.Limits{MaxDepth: -1})
+	assert.ErrorIs(t, err, folder.ErrInvalidLimits)
+	assert.ErrorIs(t, folder.CheckLimits([]folder.Folder{}, folder.Limits{MaxFolders: -1}), folder.ErrInvalidLimits)
+
+	_, err = folder.NewLimitedDriver(func(folders []folder.Folder) folder.IObservableDriver {
+		return struct{ folder.IObservableDriver }{folder.NewDriver(folders)}
+	}, []folder.Folder{}, folder.Limits{MaxDepth: 1})
+	assert.ErrorIs(t, err, folder.ErrLimitsUnsupported, "Only the package's drivers enforce limits")
+	assert.NotErrorIs(t, err, folder.ErrInvalidLimits)
+}
+
+func Test_folder_LimitsOf(t *testing.T) {
+	t.Parallel()
+
+	var limits folder.Limits = folder.Limits{MaxFolders: 10, MaxDepth: 3}
+	for _, driver := range propertyDrivers {
+		t.Run(driver.testName, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := folder.NewLimitedDriver(driver.newDriver, []folder.Folder{}, limits)
+			assert.NoError(t, err)
+			assert.Equal(t, limits, folder.LimitsOf(d))
+			assert.Equal(t, folder.Limits{}, folder.LimitsOf(driver.newDriver([]folder.Folder{})), "Drivers are unlimited by default")
+		})
+	}
+	assert.Equal(t, folder.Limits{}, folder.LimitsOf(struct{ folder.IDriver }{folder.NewDriver([]folder.Folder{})}))
+}
+
+func Test_folder_Limits(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+	folders := []folder.Folder{
+		{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+		{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+		{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+		{Name: "delta", OrgId: validOrgId, Paths: "alpha.delta"},
+		{Name: "echo", OrgId: validOrgId, Paths: "echo"},
+		{Name: "foxtrot", OrgId: otherOrgId, Paths: "foxtrot"},
+	}
+
+	tests := [...]struct {
+		testName string
+		limits   folder.Limits
+		mutate   func(d folder.IObservableDriver) ([]folder.Folder, error)
+		err      error
+	}{
+		{
+			testName: "Create within every limit",
+			limits:   folder.Limits{MaxFolders: 6, MaxDepth: 3, MaxChildren: 3, MaxPathLength: 19},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				return d.CreateFolder(validOrgId, "golf", "alpha.delta")
+			},
+		},
+		{
+			testName: "Create beyond MaxFolders",
+			limits:   folder.Limits{MaxFolders: 5},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				return d.CreateFolder(validOrgId, "golf", "")
+			},
+			err: folder.ErrTooManyFolders,
+		},
+		{
+			testName: "MaxFolders is per org",
+			limits:   folder.Limits{MaxFolders: 5},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				return d.CreateFolder(otherOrgId, "golf", "foxtrot")
+			},
+		},
+		{
+			testName: "MaxFolders applies to new orgs",
+			limits:   folder.Limits{MaxFolders: 5},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				var newOrgId = uuid.FromStringOrNil("38b9879b-f73b-4b0e-b9d9-4fc4c23643a7")
+				for _, name := range []string{"golf", "hotel", "india", "juliett", "kilo"} {
+					if _, err := d.CreateFolder(newOrgId, name, ""); err != nil {
+						return nil, err
+					}
+				}
+				return d.CreateFolder(newOrgId, "lima", "")
+			},
+			err: folder.ErrTooManyFolders,
+		},
+		{
+			testName: "Create beyond MaxDepth",
+			limits:   folder.Limits{MaxDepth: 3},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				return d.CreateFolder(validOrgId, "golf", "alpha.bravo.charlie")
+			},
+			err: folder.ErrTooDeep,
+		},
+		{
+			testName: "Create beyond MaxChildren",
+			limits:   folder.Limits{MaxChildren: 2},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				return d.CreateFolder(validOrgId, "golf", "alpha")
+			},
+			err: folder.ErrTooManyChildren,
+		},
+		{
+			testName: "Roots are not children",
+			limits:   folder.Limits{MaxChildren: 2},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				return d.CreateFolder(validOrgId, "golf", "")
+			},
+		},
+		{
+			testName: "Create beyond MaxPathLength",
+			limits:   folder.Limits{MaxPathLength: 19},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				return d.CreateFolder(validOrgId, "golfhotel", "alpha.delta")
+			},
+			err: folder.ErrPathTooLong,
+		},
+		{
+			testName: "Move deepening a subtree beyond MaxDepth",
+			limits:   folder.Limits{MaxDepth: 3},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				return d.MoveFolder("bravo", "delta")
+			},
+			err: folder.ErrTooDeep,
+		},
+		{
+			testName: "Move of a leaf within MaxDepth",
+			limits:   folder.Limits{MaxDepth: 3},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				return d.MoveFolder("charlie", "delta")
+			},
+		},
+		{
+			testName: "Move lengthening a descendant's path beyond MaxPathLength",
+			limits:   folder.Limits{MaxPathLength: 20},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				return d.MoveFolderByPath(validOrgId, "alpha.bravo", "alpha.delta")
+			},
+			err: folder.ErrPathTooLong,
+		},
+		{
+			testName: "Move beyond MaxChildren",
+			limits:   folder.Limits{MaxChildren: 2},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				return d.MoveFolderByPath(validOrgId, "echo", "alpha")
+			},
+			err: folder.ErrTooManyChildren,
+		},
+		{
+			testName: "Move to the current parent at MaxChildren",
+			limits:   folder.Limits{MaxChildren: 2},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				return d.MoveFolder("bravo", "alpha")
+			},
+		},
+		{
+			testName: "Rename lengthening a descendant's path beyond MaxPathLength",
+			limits:   folder.Limits{MaxPathLength: 19},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				return d.RenameFolder(validOrgId, "alpha", "alphabet")
+			},
+			err: folder.ErrPathTooLong,
+		},
+		{
+			testName: "Rename at MaxChildren",
+			limits:   folder.Limits{MaxChildren: 2},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				return d.RenameFolder(validOrgId, "alpha.delta", "golf")
+			},
+		},
+		{
+			testName: "Versioned mutations are limited too",
+			limits:   folder.Limits{MaxDepth: 3},
+			mutate: func(d folder.IObservableDriver) ([]folder.Folder, error) {
+				folders, _, err := d.CreateFolderIfVersion(validOrgId, 0, "golf", "alpha.bravo.charlie")
+				return folders, err
+			},
+			err: folder.ErrTooDeep,
+		},
+	}
+
+	for _, driver := range propertyDrivers {
+		t.Run(driver.testName, func(t *testing.T) {
+			t.Parallel()
+
+			for _, tt := range tests {
+				t.Run(tt.testName, func(t *testing.T) {
+					d, err := folder.NewLimitedDriver(driver.newDriver, append([]folder.Folder{}, folders...), tt.limits)
+					require.NoError(t, err)
+
+					before := driverFolders(d)
+					_, err = tt.mutate(d)
+					if tt.err == nil {
+						assert.NoError(t, err)
+						return
+					}
+					assert.ErrorIs(t, err, tt.err)
+					assert.Equal(t, before, driverFolders(d), "A mutation beyond a limit changes nothing")
+				})
+			}
+		})
+	}
+}
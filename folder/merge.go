@@ -0,0 +1,333 @@
+package folder
+
+import (
+	"fmt"
+
+	"github.com/gofrs/uuid"
+)
+
+// ConflictKind is why changes made on both sides of a merge could not be combined
+type ConflictKind string
+
+const (
+	// ConflictMovedTwice is a folder moved or renamed differently by each side. It stays where it was in base.
+	ConflictMovedTwice ConflictKind = "moved_twice"
+
+	// ConflictChangedDeleted is a folder moved or renamed by one side but deleted by the other. It is deleted.
+	ConflictChangedDeleted ConflictKind = "changed_deleted"
+
+	// ConflictMovedIntoDeleted is a folder moved or created by one side beneath one deleted by the other.
+	// A moved folder stays where it was in base, and a created folder is left out.
+	ConflictMovedIntoDeleted ConflictKind = "moved_into_deleted"
+
+	// ConflictCycle is a folder whose move, combined with a move by the other side, would place it beneath itself.
+	// It stays where it was in base.
+	ConflictCycle ConflictKind = "cycle"
+
+	// ConflictPathTaken is a folder moved, renamed or created by theirs to a path ours has given another folder.
+	// Theirs' change is left out.
+	ConflictPathTaken ConflictKind = "path_taken"
+)
+
+// Conflict is a change to a folder which was left out of a merge
+type Conflict struct {
+	Kind  ConflictKind `json:"kind"`
+	OrgId uuid.UUID    `json:"org_id"`
+
+	// Path is the folder's path in base, or where it was created if it is new.
+	Path string `json:"path"`
+
+	// Ours and Theirs are the folder's paths on each side, empty where it does not exist.
+	Ours   string `json:"ours,omitempty"`
+	Theirs string `json:"theirs,omitempty"`
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s %s: ours %q, theirs %q", c.Kind, c.Path, c.Ours, c.Theirs)
+}
+
+// Merge combines the changes made by ours and theirs to the folders of base.
+// Changes to different folders are combined, and a change made on one side only is kept.
+// Changes which cannot be combined are left out, as described by each ConflictKind, and reported;
+// the merged folders are always well-formed.
+// Folders are matched across the sides the same way Diff matches them.
+func Merge(base []Folder, ours []Folder, theirs []Folder) ([]Folder, []Conflict) {
+	orgs := []uuid.UUID{}
+	byOrg := [3]map[uuid.UUID][]Folder{{}, {}, {}}
+	for i, folders := range [3][]Folder{base, ours, theirs} {
+		for _, f := range folders {
+			if _, seen := byOrg[0][f.OrgId]; !seen && byOrg[1][f.OrgId] == nil && byOrg[2][f.OrgId] == nil {
+				orgs = append(orgs, f.OrgId)
+			}
+			byOrg[i][f.OrgId] = append(byOrg[i][f.OrgId], f)
+		}
+	}
+
+	merged := []Folder{}
+	conflicts := []Conflict{}
+	for _, orgID := range orgs {
+		m := newOrgMerge(orgID, byOrg[0][orgID], byOrg[1][orgID], byOrg[2][orgID])
+		m.combine()
+		m.resolve()
+		merged = append(merged, m.folders()...)
+		conflicts = append(conflicts, m.conflicts...)
+	}
+	return merged, conflicts
+}
+
+// Which of the sides a folder's merged place comes from
+type mergeSide int
+
+const (
+	fromBase mergeSide = iota
+	fromOurs
+	fromTheirs
+)
+
+// Where a folder is on one side: beneath the folder with id parent, or at the root if it is empty
+type placement struct {
+	present bool
+	parent  string
+	name    string
+}
+
+// The folders of one side, by id: the base path of a folder in base, or "." and the path of a created folder,
+// which cannot be mistaken for a path as labels are never empty
+type mergeSideState struct {
+	place map[string]placement
+	path  map[string]string
+	order []string
+}
+
+// orgMerge merges the folders of one organisation
+type orgMerge struct {
+	orgID     uuid.UUID
+	sides     [3]mergeSideState // base, ours and theirs
+	merged    map[string]placement
+	from      map[string]mergeSide
+	ids       []string // every id, base first
+	conflicts []Conflict
+}
+
+func newOrgMerge(orgID uuid.UUID, base []Folder, ours []Folder, theirs []Folder) *orgMerge {
+	m := &orgMerge{orgID: orgID, merged: map[string]placement{}, from: map[string]mergeSide{}}
+
+	m.sides[fromBase] = mergeSideState{place: map[string]placement{}, path: map[string]string{}}
+	for _, f := range base {
+		m.sides[fromBase].place[f.Paths] = placement{present: true, parent: ParentPath(f.Paths), name: f.Name}
+		m.sides[fromBase].path[f.Paths] = f.Paths
+		m.sides[fromBase].order = append(m.sides[fromBase].order, f.Paths)
+	}
+	m.sides[fromOurs] = sideState(orgID, base, ours)
+	m.sides[fromTheirs] = sideState(orgID, base, theirs)
+
+	seen := map[string]bool{}
+	for _, side := range m.sides {
+		for _, id := range side.order {
+			if !seen[id] {
+				seen[id] = true
+				m.ids = append(m.ids, id)
+			}
+		}
+	}
+	return m
+}
+
+// sideState identifies the folders of one side, matching them with base as Diff does
+func sideState(orgID uuid.UUID, base []Folder, side []Folder) mergeSideState {
+	d := newOrgDiff(orgID, base, side)
+	d.match()
+
+	var idOf func(path string) string
+	idOf = func(path string) string {
+		if origin, existed := d.originOf(path); existed {
+			return origin
+		}
+		return "." + path
+	}
+
+	state := mergeSideState{place: map[string]placement{}, path: map[string]string{}}
+	for _, f := range side {
+		var id string = idOf(f.Paths)
+		state.place[id] = placement{present: true, parent: idOf(ParentPath(f.Paths)), name: f.Name}
+		state.path[id] = f.Paths
+		state.order = append(state.order, id)
+	}
+	return state
+}
+
+// combine places each folder as the side which changed it does, or as base if both changed it differently
+func (m *orgMerge) combine() {
+	for _, id := range m.ids {
+		var b, o, t placement = m.sides[fromBase].place[id], m.sides[fromOurs].place[id], m.sides[fromTheirs].place[id]
+
+		switch {
+		case o == t:
+			m.place(id, o, fromOurs)
+		case o == b:
+			m.place(id, t, fromTheirs)
+		case t == b:
+			m.place(id, o, fromOurs)
+		case !o.present || !t.present:
+			m.conflict(ConflictChangedDeleted, id)
+			m.place(id, placement{}, fromBase)
+		default:
+			m.conflict(ConflictMovedTwice, id)
+			m.place(id, b, fromBase)
+		}
+	}
+}
+
+func (m *orgMerge) place(id string, p placement, side mergeSide) {
+	m.merged[id] = p
+	m.from[id] = side
+	if p == m.sides[fromBase].place[id] {
+		m.from[id] = fromBase
+	}
+}
+
+// revert puts a folder back where it was in base, or leaves it out if it is new
+func (m *orgMerge) revert(kind ConflictKind, id string) {
+	m.conflict(kind, id)
+	m.place(id, m.sides[fromBase].place[id], fromBase)
+}
+
+func (m *orgMerge) conflict(kind ConflictKind, id string) {
+	var path string = m.sides[fromBase].path[id]
+	if path == "" {
+		path = id[1:]
+	}
+
+	m.conflicts = append(m.conflicts, Conflict{
+		Kind:   kind,
+		OrgId:  m.orgID,
+		Path:   path,
+		Ours:   m.sides[fromOurs].path[id],
+		Theirs: m.sides[fromTheirs].path[id],
+	})
+}
+
+// resolve reverts changes until the merged folders form trees, as base does.
+// Each revert brings a folder closer to base, so this ends.
+func (m *orgMerge) resolve() {
+	for m.resolveOrphans() || m.resolveCycles() || m.resolvePaths() {
+	}
+}
+
+// resolveOrphans removes folders beneath deleted ones, reverting any which were moved or created there
+func (m *orgMerge) resolveOrphans() bool {
+	var changed bool = false
+	for _, id := range m.ids {
+		p := m.merged[id]
+		if !p.present || p.parent == "" || m.merged[p.parent].present {
+			continue
+		}
+
+		if m.from[id] == fromBase {
+			m.place(id, placement{}, fromBase)
+		} else {
+			m.revert(ConflictMovedIntoDeleted, id)
+		}
+		changed = true
+	}
+	return changed
+}
+
+// resolveCycles reverts the moves of folders which end up beneath themselves
+func (m *orgMerge) resolveCycles() bool {
+	for _, id := range m.ids {
+		visited := map[string]bool{}
+		for at := id; at != "" && m.merged[at].present; at = m.merged[at].parent {
+			if !visited[at] {
+				visited[at] = true
+				continue
+			}
+
+			// at is on a cycle, whose moved folders are put back
+			for on := at; ; {
+				var next string = m.merged[on].parent
+				if m.from[on] != fromBase {
+					m.revert(ConflictCycle, on)
+				}
+				if on = next; on == at {
+					break
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePaths reverts the changes of theirs, then ours, which give two folders the same path
+func (m *orgMerge) resolvePaths() bool {
+	paths := m.paths()
+	taken := map[string][]string{}
+	for _, id := range m.ids {
+		if path, present := paths[id]; present {
+			taken[path] = append(taken[path], id)
+		}
+	}
+
+	var changed bool = false
+	for _, id := range m.ids {
+		var sharing []string = taken[paths[id]]
+		if !m.merged[id].present || len(sharing) < 2 {
+			continue
+		}
+
+		// Keep the folder placed by base, or failing that by ours, so revert the others
+		var keep string = sharing[0]
+		for _, other := range sharing {
+			if m.from[other] < m.from[keep] || (m.from[other] == m.from[keep] && other < keep) {
+				keep = other
+			}
+		}
+		if id != keep && m.from[id] != fromBase {
+			m.revert(ConflictPathTaken, id)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// paths works out the path of each merged folder, which must form trees
+func (m *orgMerge) paths() map[string]string {
+	paths := map[string]string{}
+	var pathOf func(id string) string
+	pathOf = func(id string) string {
+		if path, found := paths[id]; found {
+			return path
+		}
+		p := m.merged[id]
+		var path string = p.name
+		if p.parent != "" {
+			path = pathOf(p.parent) + "." + p.name
+		}
+		paths[id] = path
+		return path
+	}
+
+	for _, id := range m.ids {
+		if m.merged[id].present {
+			pathOf(id)
+		}
+	}
+	return paths
+}
+
+// folders returns the merged folders, in the order of ours followed by those new in theirs
+func (m *orgMerge) folders() []Folder {
+	paths := m.paths()
+	result := []Folder{}
+	added := map[string]bool{}
+	for _, side := range [...]mergeSide{fromOurs, fromTheirs, fromBase} {
+		for _, id := range m.sides[side].order {
+			if p := m.merged[id]; p.present && !added[id] {
+				added[id] = true
+				result = append(result, Folder{Name: p.name, OrgId: m.orgID, Paths: paths[id]})
+			}
+		}
+	}
+	return result
+}
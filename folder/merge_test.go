@@ -0,0 +1,252 @@
+package folder_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_Merge(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+
+	base := []folder.Folder{
+		{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+		{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+		{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+		{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+		{Name: "echo", OrgId: validOrgId, Paths: "delta.echo"},
+		{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+	}
+
+	tests := [...]struct {
+		testName  string
+		ours      []folder.Folder
+		theirs    []folder.Folder
+		want      []folder.Folder
+		conflicts []folder.Conflict
+	}{
+		{
+			testName: "Independent moves are combined",
+			ours: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "foxtrot.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "foxtrot.bravo.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "delta.echo"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+			},
+			theirs: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "alpha.delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "alpha.delta.echo"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+				{Name: "golf", OrgId: validOrgId, Paths: "foxtrot.golf"},
+			},
+			want: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "foxtrot.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "foxtrot.bravo.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "alpha.delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "alpha.delta.echo"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+				{Name: "golf", OrgId: validOrgId, Paths: "foxtrot.golf"},
+			},
+			conflicts: []folder.Conflict{},
+		},
+		{
+			testName: "The same change on both sides is made once",
+			ours: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "delta.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "delta.echo"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+				{Name: "golf", OrgId: validOrgId, Paths: "golf"},
+			},
+			theirs: []folder.Folder{
+				{Name: "golf", OrgId: validOrgId, Paths: "golf"},
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "delta.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "delta.echo"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+			},
+			want: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "delta.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "delta.echo"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+				{Name: "golf", OrgId: validOrgId, Paths: "golf"},
+			},
+			conflicts: []folder.Conflict{},
+		},
+		{
+			testName: "Folder moved to two places stays put",
+			ours: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "delta.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "delta.bravo.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "delta.echo"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+			},
+			theirs: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "foxtrot.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "foxtrot.bravo.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "delta.echo"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+			},
+			want: base,
+			conflicts: []folder.Conflict{
+				{Kind: folder.ConflictMovedTwice, OrgId: validOrgId, Path: "alpha.bravo", Ours: "delta.bravo", Theirs: "foxtrot.bravo"},
+			},
+		},
+		{
+			testName: "Move into a deleted subtree",
+			ours: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "delta.echo.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "delta.echo.bravo.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "delta.echo"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+			},
+			theirs: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+			},
+			want: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+			},
+			conflicts: []folder.Conflict{
+				{Kind: folder.ConflictMovedIntoDeleted, OrgId: validOrgId, Path: "alpha.bravo", Ours: "delta.echo.bravo", Theirs: "alpha.bravo"},
+			},
+		},
+		{
+			testName: "Moved folder deleted by the other side",
+			ours: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+				{Name: "echo", OrgId: validOrgId, Paths: "foxtrot.echo"},
+			},
+			theirs: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+			},
+			want: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+			},
+			conflicts: []folder.Conflict{
+				{Kind: folder.ConflictChangedDeleted, OrgId: validOrgId, Path: "delta.echo", Ours: "foxtrot.echo"},
+			},
+		},
+		{
+			testName: "Two moves which would create a cycle",
+			ours: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "delta.alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "delta.alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "delta.alpha.bravo.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "delta.echo"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+			},
+			theirs: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "alpha.bravo.delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "alpha.bravo.delta.echo"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+			},
+			want: base,
+			conflicts: []folder.Conflict{
+				{Kind: folder.ConflictCycle, OrgId: validOrgId, Path: "alpha", Ours: "delta.alpha", Theirs: "alpha"},
+				{Kind: folder.ConflictCycle, OrgId: validOrgId, Path: "delta", Ours: "delta", Theirs: "alpha.bravo.delta"},
+			},
+		},
+		{
+			testName: "Folder created where the other side moved one",
+			ours: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "foxtrot.echo"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+			},
+			theirs: append([]folder.Folder{
+				{Name: "echo", OrgId: validOrgId, Paths: "foxtrot.echo"},
+			}, base...),
+			want: []folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+				{Name: "delta", OrgId: validOrgId, Paths: "delta"},
+				{Name: "echo", OrgId: validOrgId, Paths: "foxtrot.echo"},
+				{Name: "foxtrot", OrgId: validOrgId, Paths: "foxtrot"},
+			},
+			conflicts: []folder.Conflict{
+				{Kind: folder.ConflictPathTaken, OrgId: validOrgId, Path: "foxtrot.echo", Theirs: "foxtrot.echo"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			merged, conflicts := folder.Merge(base, tt.ours, tt.theirs)
+			assert.ElementsMatch(t, tt.want, merged)
+			assert.Equal(t, tt.conflicts, conflicts)
+			assert.Empty(t, folder.Validate(merged), "Merged folders are well-formed")
+		})
+	}
+}
+
+// Test_folder_Merge_Random merges random changes to the sample data, checking the result is always well-formed
+func Test_folder_Merge_Random(t *testing.T) {
+	t.Parallel()
+
+	var orgId = uuid.FromStringOrNil(folder.DefaultOrgID)
+	var base []folder.Folder = folder.NewDriver(folder.GetSampleData()).GetFoldersByOrgID(orgId)
+	rng := rand.New(rand.NewSource(1))
+
+	for round := 0; round < 20; round++ {
+		ours, theirs := folder.NewDriver(base), folder.NewDriver(base)
+		randomEdits(ours, orgId, rng, 5)
+		randomEdits(theirs, orgId, rng, 5)
+
+		merged, _ := folder.Merge(base, ours.GetFoldersByOrgID(orgId), theirs.GetFoldersByOrgID(orgId))
+		assert.Empty(t, folder.Validate(merged), "Round %d", round)
+
+		merged, conflicts := folder.Merge(base, ours.GetFoldersByOrgID(orgId), base)
+		assert.ElementsMatch(t, ours.GetFoldersByOrgID(orgId), merged, "Round %d, merging with no changes", round)
+		assert.Empty(t, conflicts)
+	}
+}
@@ -1,24 +1,25 @@
 package folder
 
 import (
-	"errors"
 	"strings"
+
+	"github.com/gofrs/uuid"
 )
 
 func (f *driver) MoveFolder(name string, dst string) ([]Folder, error) {
 	if name == dst {
-		return []Folder{}, errors.New("cannot move a folder to itself")
+		return []Folder{}, ErrMoveToSelf
 	}
 
 	var srcFolders []Folder = findFoldersByName(&f.folders, name)
 	var dstFolders []Folder = findFoldersByName(&f.folders, dst)
 
 	if len(srcFolders) == 0 {
-		return []Folder{}, errors.New("source folder does not exist")
+		return []Folder{}, ErrSourceNotFound
 	}
 
 	if len(dstFolders) == 0 {
-		return []Folder{}, errors.New("destination folder does not exist")
+		return []Folder{}, ErrDestinationNotFound
 	}
 
 	// Refer to Assumption
@@ -26,11 +27,15 @@ func (f *driver) MoveFolder(name string, dst string) ([]Folder, error) {
 	var dstFolder Folder = dstFolders[0]
 
 	if srcFolder.OrgId != dstFolder.OrgId {
-		return []Folder{}, errors.New("cannot move a folder to a different organization")
+		return []Folder{}, ErrMoveToOtherOrg
 	}
 
 	if isChildFolder(&srcFolder, &dstFolder) {
-		return []Folder{}, errors.New("cannot move a folder to a child of itself")
+		return []Folder{}, ErrMoveToChild
+	}
+
+	if err := f.checkMove(srcFolder, dstFolder.Paths+"."+srcFolder.Name); err != nil {
+		return []Folder{}, err
 	}
 
 	resultAfterMove := []Folder{}
@@ -43,13 +48,59 @@ func (f *driver) MoveFolder(name string, dst string) ([]Folder, error) {
 		}
 	}
 
+	var before []Folder = f.folders
+	f.folders = resultAfterMove
+	f.bump(srcFolder.OrgId)
+	f.publishChanges(before, resultAfterMove)
 	return resultAfterMove, nil
 }
 
+func (f *driver) MoveFolderByPath(orgID uuid.UUID, src string, dst string) ([]Folder, error) {
+	if src == dst {
+		return []Folder{}, ErrMoveToSelf
+	}
+
+	srcFolder, found := findFolderByPath(&f.folders, orgID, src)
+	if !found {
+		return []Folder{}, ErrSourceNotFound
+	}
+
+	var newPath string = srcFolder.Name
+	if dst != "" {
+		dstFolder, found := findFolderByPath(&f.folders, orgID, dst)
+		if !found {
+			return []Folder{}, ErrDestinationNotFound
+		}
+
+		if IsDescendantPath(srcFolder.Paths, dstFolder.Paths) {
+			return []Folder{}, ErrMoveToChild
+		}
+
+		newPath = dstFolder.Paths + "." + srcFolder.Name
+	}
+
+	if newPath == srcFolder.Paths {
+		return f.folders, nil
+	}
+
+	if _, exists := findFolderByPath(&f.folders, orgID, newPath); exists {
+		return []Folder{}, ErrFolderExists
+	}
+
+	if err := f.checkMove(srcFolder, newPath); err != nil {
+		return []Folder{}, err
+	}
+
+	var before []Folder = f.folders
+	f.folders = rebaseFolders(&f.folders, orgID, srcFolder.Paths, newPath)
+	f.bump(orgID)
+	f.publishChanges(before, f.folders)
+	return f.folders, nil
+}
+
 // isMovedFolder checks if the folder needs to be moved, as it is part of srcFolder
 func isMovedFolder(folder *Folder, srcFolder *Folder) bool {
-	return srcFolder.Name == folder.Name || // Refer to assumption
-		isChildFolder(srcFolder, folder)
+	return *folder == *srcFolder || isChildFolder(srcFolder, folder)
 }
 
 // getNewMovedFolder creates a new folder with the path adjusted after being moved to dstFolder
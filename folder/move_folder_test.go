@@ -104,6 +104,38 @@ func Test_folder_MoveFolder(t *testing.T) {
 				{Name: "c1-nest", OrgId: validOrgId, Paths: "fold.c1.c1-nest"},
 			},
 		},
+		{
+			testName: "Folders whose names begin with the source's stay put",
+			src:      "fold",
+			dst:      "a",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "c1", OrgId: validOrgId, Paths: "fold.c1"},
+				{Name: "folder", OrgId: validOrgId, Paths: "folder"},
+				{Name: "c2", OrgId: validOrgId, Paths: "folder.c2"},
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+			},
+			want: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "a.fold"},
+				{Name: "c1", OrgId: validOrgId, Paths: "a.fold.c1"},
+				{Name: "folder", OrgId: validOrgId, Paths: "folder"},
+				{Name: "c2", OrgId: validOrgId, Paths: "folder.c2"},
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+			},
+		},
+		{
+			testName: "Move into a folder whose name begins with the source's",
+			src:      "fold",
+			dst:      "folder",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "folder", OrgId: validOrgId, Paths: "folder"},
+			},
+			want: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "folder.fold"},
+				{Name: "folder", OrgId: validOrgId, Paths: "folder"},
+			},
+		},
 		{
 			testName: "Error: Cannot move a folder to a child of itself",
 			src:      "c1",
@@ -168,3 +200,158 @@ func Test_folder_MoveFolder(t *testing.T) {
 		})
 	}
 }
+
+func Test_folder_MoveFolderByPath(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+	tests := [...]struct {
+		testName string
+		src      string
+		dst      string
+		folders  []folder.Folder
+		want     []folder.Folder
+		err      error
+	}{
+		{
+			testName: "Error: Target path already exists",
+			src:      "b.c",
+			dst:      "a",
+			folders: []folder.Folder{
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+				{Name: "c", OrgId: validOrgId, Paths: "a.c"},
+				{Name: "b", OrgId: validOrgId, Paths: "b"},
+				{Name: "c", OrgId: validOrgId, Paths: "b.c"},
+				{Name: "d", OrgId: validOrgId, Paths: "b.c.d"},
+			},
+			err: folder.ErrFolderExists,
+		},
+		{
+			testName: "Move nested folder to another parent",
+			src:      "b.c",
+			dst:      "a.c",
+			folders: []folder.Folder{
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+				{Name: "c", OrgId: validOrgId, Paths: "a.c"},
+				{Name: "b", OrgId: validOrgId, Paths: "b"},
+				{Name: "c", OrgId: validOrgId, Paths: "b.c"},
+				{Name: "d", OrgId: validOrgId, Paths: "b.c.d"},
+				{Name: "b", OrgId: otherOrgId, Paths: "b"},
+				{Name: "c", OrgId: otherOrgId, Paths: "b.c"},
+			},
+			want: []folder.Folder{
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+				{Name: "c", OrgId: validOrgId, Paths: "a.c"},
+				{Name: "b", OrgId: validOrgId, Paths: "b"},
+				{Name: "c", OrgId: validOrgId, Paths: "a.c.c"},
+				{Name: "d", OrgId: validOrgId, Paths: "a.c.c.d"},
+				{Name: "b", OrgId: otherOrgId, Paths: "b"},
+				{Name: "c", OrgId: otherOrgId, Paths: "b.c"},
+			},
+		},
+		{
+			testName: "Move nested folder to the root",
+			src:      "b.c",
+			dst:      "",
+			folders: []folder.Folder{
+				{Name: "b", OrgId: validOrgId, Paths: "b"},
+				{Name: "c", OrgId: validOrgId, Paths: "b.c"},
+				{Name: "d", OrgId: validOrgId, Paths: "b.c.d"},
+			},
+			want: []folder.Folder{
+				{Name: "b", OrgId: validOrgId, Paths: "b"},
+				{Name: "c", OrgId: validOrgId, Paths: "c"},
+				{Name: "d", OrgId: validOrgId, Paths: "c.d"},
+			},
+		},
+		{
+			testName: "Label prefixed sibling is not a child",
+			src:      "b",
+			dst:      "bb",
+			folders: []folder.Folder{
+				{Name: "b", OrgId: validOrgId, Paths: "b"},
+				{Name: "bb", OrgId: validOrgId, Paths: "bb"},
+			},
+			want: []folder.Folder{
+				{Name: "b", OrgId: validOrgId, Paths: "bb.b"},
+				{Name: "bb", OrgId: validOrgId, Paths: "bb"},
+			},
+		},
+		{
+			testName: "Error: Cannot move a folder to a child of itself",
+			src:      "b",
+			dst:      "b.c",
+			folders: []folder.Folder{
+				{Name: "b", OrgId: validOrgId, Paths: "b"},
+				{Name: "c", OrgId: validOrgId, Paths: "b.c"},
+			},
+			err: folder.ErrMoveToChild,
+		},
+		{
+			testName: "Error: Cannot move a folder to itself",
+			src:      "b",
+			dst:      "b",
+			folders: []folder.Folder{
+				{Name: "b", OrgId: validOrgId, Paths: "b"},
+			},
+			err: folder.ErrMoveToSelf,
+		},
+		{
+			testName: "Error: Source folder does not exist in organisation",
+			src:      "b",
+			dst:      "a",
+			folders: []folder.Folder{
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+				{Name: "b", OrgId: otherOrgId, Paths: "b"},
+			},
+			err: folder.ErrSourceNotFound,
+		},
+		{
+			testName: "Error: Destination folder does not exist",
+			src:      "b",
+			dst:      "missing",
+			folders: []folder.Folder{
+				{Name: "b", OrgId: validOrgId, Paths: "b"},
+			},
+			err: folder.ErrDestinationNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			f := folder.NewDriver(tt.folders)
+			folders, err := f.MoveFolderByPath(validOrgId, tt.src, tt.dst)
+
+			if tt.err == nil {
+				assert.NoError(t, err, tt.testName)
+				assert.Equal(t, tt.want, folders, tt.testName)
+			} else {
+				assert.ErrorIs(t, err, tt.err, tt.testName)
+			}
+		})
+	}
+}
+
+func Test_folder_MoveFolder_KeepsResult(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+
+	f := folder.NewDriver([]folder.Folder{
+		{Name: "a", OrgId: validOrgId, Paths: "a"},
+		{Name: "b", OrgId: validOrgId, Paths: "b"},
+		{Name: "c", OrgId: validOrgId, Paths: "c"},
+	})
+
+	_, err := f.MoveFolder("b", "a")
+	assert.NoError(t, err)
+	folders, err := f.MoveFolder("c", "b")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []folder.Folder{
+		{Name: "a", OrgId: validOrgId, Paths: "a"},
+		{Name: "b", OrgId: validOrgId, Paths: "a.b"},
+		{Name: "c", OrgId: validOrgId, Paths: "a.b.c"},
+	}, folders)
+}
@@ -0,0 +1,76 @@
+package folder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gofrs/uuid"
+)
+
+// maxNDJSONLine is the longest line ReadNDJSON accepts, which deep paths can make long
+const maxNDJSONLine = 16 << 20
+
+// ReadNDJSON reads folders from newline delimited JSON, one folder object per line.
+// Blank lines are skipped, and folders may come in any order.
+func ReadNDJSON(r io.Reader) ([]Folder, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxNDJSONLine)
+
+	folders := []Folder{}
+	lines := []int{}
+	seenPaths := map[uuid.UUID]map[string]bool{}
+
+	for line := 1; scanner.Scan(); line++ {
+		record := bytes.TrimSpace(scanner.Bytes())
+		if len(record) == 0 {
+			continue
+		}
+
+		var f Folder
+		decoder := json.NewDecoder(bytes.NewReader(record))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&f); err != nil {
+			return nil, &ParseError{Line: line, Err: err}
+		}
+		if err := checkFolder(&f); err != nil {
+			return nil, &ParseError{Line: line, Err: fmt.Errorf("%w: %q", err, f.Paths)}
+		}
+
+		if seenPaths[f.OrgId] == nil {
+			seenPaths[f.OrgId] = map[string]bool{}
+		}
+		if seenPaths[f.OrgId][f.Paths] {
+			return nil, &ParseError{Line: line, Err: fmt.Errorf("%w: %q", ErrFolderExists, f.Paths)}
+		}
+		seenPaths[f.OrgId][f.Paths] = true
+
+		folders = append(folders, f)
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, f := range folders {
+		if parent := ParentPath(f.Paths); parent != "" && !seenPaths[f.OrgId][parent] {
+			return nil, &ParseError{Line: lines[i], Err: fmt.Errorf("%w: parent of %q", ErrFolderNotFound, f.Paths)}
+		}
+	}
+
+	return folders, nil
+}
+
+// WriteNDJSON writes folders as newline delimited JSON, one folder object per line
+func WriteNDJSON(w io.Writer, folders []Folder) error {
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+	for _, f := range folders {
+		if err := encoder.Encode(f); err != nil {
+			return err
+		}
+	}
+	return buffered.Flush()
+}
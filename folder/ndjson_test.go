@@ -0,0 +1,102 @@
+package folder_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_ReadNDJSON(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+
+	tests := [...]struct {
+		testName string
+		input    string
+		want     []folder.Folder
+		errLine  int
+	}{
+		{
+			testName: "Children before parents, with blank lines",
+			input: `{"name": "bravo", "org_id": "c59cc5c1-9b81-4d00-95e3-22c6efdaf134", "paths": "alpha.bravo"}` + "\n\n" +
+				`{"name": "alpha", "org_id": "c59cc5c1-9b81-4d00-95e3-22c6efdaf134", "paths": "alpha"}`,
+			want: []folder.Folder{
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+			},
+		},
+		{
+			testName: "Empty input",
+			input:    "",
+			want:     []folder.Folder{},
+		},
+		{
+			testName: "Error: Malformed JSON",
+			input:    `{"name": "alpha", "org_id": "c59cc5c1-9b81-4d00-95e3-22c6efdaf134", "paths": "alpha"}` + "\n" + `{"name": "bravo",`,
+			errLine:  2,
+		},
+		{
+			testName: "Error: Unknown field",
+			input:    `{"name": "alpha", "org_id": "c59cc5c1-9b81-4d00-95e3-22c6efdaf134", "paths": "alpha", "size": 1}`,
+			errLine:  1,
+		},
+		{
+			testName: "Error: Name is not the last label",
+			input: `{"name": "alpha", "org_id": "c59cc5c1-9b81-4d00-95e3-22c6efdaf134", "paths": "alpha"}` + "\n\n" +
+				`{"name": "bravo", "org_id": "c59cc5c1-9b81-4d00-95e3-22c6efdaf134", "paths": "alpha.charlie"}`,
+			errLine: 3,
+		},
+		{
+			testName: "Error: Duplicate path",
+			input: `{"name": "alpha", "org_id": "c59cc5c1-9b81-4d00-95e3-22c6efdaf134", "paths": "alpha"}` + "\n" +
+				`{"name": "alpha", "org_id": "c59cc5c1-9b81-4d00-95e3-22c6efdaf134", "paths": "alpha"}`,
+			errLine: 2,
+		},
+		{
+			testName: "Error: Missing parent",
+			input: `{"name": "alpha", "org_id": "c59cc5c1-9b81-4d00-95e3-22c6efdaf134", "paths": "alpha"}` + "\n" +
+				`{"name": "charlie", "org_id": "c59cc5c1-9b81-4d00-95e3-22c6efdaf134", "paths": "alpha.bravo.charlie"}`,
+			errLine: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			folders, err := folder.ReadNDJSON(strings.NewReader(tt.input))
+
+			if tt.errLine == 0 {
+				assert.NoError(t, err, tt.testName)
+				assert.Equal(t, tt.want, folders, tt.testName)
+			} else {
+				var parseErr *folder.ParseError
+				if assert.True(t, errors.As(err, &parseErr), tt.testName) {
+					assert.Equal(t, tt.errLine, parseErr.Line, tt.testName)
+				}
+			}
+		})
+	}
+}
+
+func Test_folder_WriteNDJSON(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	folders := []folder.Folder{
+		{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+		{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, folder.WriteNDJSON(&buf, folders))
+	assert.Equal(t, `{"name":"alpha","org_id":"c59cc5c1-9b81-4d00-95e3-22c6efdaf134","paths":"alpha"}`+"\n"+
+		`{"name":"bravo","org_id":"c59cc5c1-9b81-4d00-95e3-22c6efdaf134","paths":"alpha.bravo"}`+"\n", buf.String())
+
+	read, err := folder.ReadNDJSON(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, folders, read, "Round trips")
+}
@@ -0,0 +1,139 @@
+package folder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// PatchError reports the change of a patch which could not be read or made, by its index
+type PatchError struct {
+	Index  int
+	Change Change
+	Err    error
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("change %d (%s): %v", e.Index, e.Change, e.Err)
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// WritePatch writes a ChangeSet as a JSON patch, which ReadPatch reads back
+func WritePatch(w io.Writer, patch ChangeSet) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(patch)
+}
+
+// ReadPatch reads a JSON patch, checking each change is well-formed
+func ReadPatch(r io.Reader) (ChangeSet, error) {
+	var patch ChangeSet
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patch); err != nil {
+		return ChangeSet{}, err
+	}
+
+	for i, c := range patch.Changes {
+		if err := checkChange(&c); err != nil {
+			return ChangeSet{}, &PatchError{Index: i, Change: c, Err: err}
+		}
+	}
+	return patch, nil
+}
+
+// checkChange checks a change has valid paths for its kind: a move keeps the folder's name,
+// and a rename keeps its parent
+func checkChange(c *Change) error {
+	paths := []string{c.Path}
+	switch c.Kind {
+	case ChangeCreate, ChangeDelete:
+		if c.NewPath != "" {
+			return ErrInvalidChange
+		}
+	case ChangeMove, ChangeRename:
+		paths = append(paths, c.NewPath)
+	default:
+		return ErrInvalidChange
+	}
+
+	for _, path := range paths {
+		if err := checkFolder(&Folder{Name: lastLabel(path), Paths: path}); err != nil {
+			return err
+		}
+	}
+
+	if c.Kind == ChangeMove && lastLabel(c.Path) != lastLabel(c.NewPath) {
+		return ErrInvalidChange
+	}
+	if c.Kind == ChangeRename && ParentPath(c.Path) != ParentPath(c.NewPath) {
+		return ErrInvalidChange
+	}
+	return nil
+}
+
+// lastLabel returns the name of the folder at path
+func lastLabel(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
+
+// ApplyPatch makes the changes of a patch to a driver, in order.
+// Each change must find the folders it names where it expects them: the folder it changes at Path,
+// no folder already at the path it creates, moves or renames to, and the parent of that path.
+// The patch is first made to a copy of the folders it touches, so a patch which cannot be made
+// leaves the driver untouched. Should the driver's folders be changed meanwhile, the returned
+// PatchError's Index is the change which failed, and those before it have been made.
+func ApplyPatch(d IStatefulDriver, patch ChangeSet) error {
+	orgs := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{}
+	for _, c := range patch.Changes {
+		if !seen[c.OrgId] {
+			seen[c.OrgId] = true
+			orgs = append(orgs, c.OrgId)
+		}
+	}
+
+	copied := []Folder{}
+	for _, orgID := range orgs {
+		copied = append(copied, d.GetFoldersByOrgID(orgID)...)
+	}
+	if err := applyChanges(NewDriver(copied), patch); err != nil {
+		return err
+	}
+
+	return applyChanges(d, patch)
+}
+
+func applyChanges(d IStatefulDriver, patch ChangeSet) error {
+	for i, c := range patch.Changes {
+		if err := applyChange(d, &c); err != nil {
+			return &PatchError{Index: i, Change: c, Err: err}
+		}
+	}
+	return nil
+}
+
+func applyChange(d IStatefulDriver, c *Change) error {
+	if err := checkChange(c); err != nil {
+		return err
+	}
+
+	var err error
+	switch c.Kind {
+	case ChangeCreate:
+		_, err = d.CreateFolder(c.OrgId, lastLabel(c.Path), ParentPath(c.Path))
+	case ChangeDelete:
+		_, err = d.DeleteFolder(c.OrgId, c.Path)
+	case ChangeMove:
+		_, err = d.MoveFolderByPath(c.OrgId, c.Path, ParentPath(c.NewPath))
+	case ChangeRename:
+		_, err = d.RenameFolder(c.OrgId, c.Path, lastLabel(c.NewPath))
+	}
+	return err
+}
@@ -0,0 +1,144 @@
+package folder_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_folder_ApplyPatch(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+	folders := []folder.Folder{
+		{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+		{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+		{Name: "charlie", OrgId: validOrgId, Paths: "charlie"},
+		{Name: "other", OrgId: otherOrgId, Paths: "other"},
+	}
+
+	tests := [...]struct {
+		testName string
+		changes  []folder.Change
+		want     []folder.Folder
+		index    int
+		err      error
+	}{
+		{
+			testName: "Changes made in order",
+			changes: []folder.Change{
+				{Kind: folder.ChangeCreate, OrgId: validOrgId, Path: "charlie.delta"},
+				{Kind: folder.ChangeMove, OrgId: validOrgId, Path: "alpha.bravo", NewPath: "charlie.delta.bravo"},
+				{Kind: folder.ChangeRename, OrgId: validOrgId, Path: "charlie", NewPath: "echo"},
+				{Kind: folder.ChangeDelete, OrgId: validOrgId, Path: "alpha"},
+			},
+			want: []folder.Folder{
+				{Name: "bravo", OrgId: validOrgId, Paths: "echo.delta.bravo"},
+				{Name: "echo", OrgId: validOrgId, Paths: "echo"},
+				{Name: "delta", OrgId: validOrgId, Paths: "echo.delta"},
+				{Name: "other", OrgId: otherOrgId, Paths: "other"},
+			},
+		},
+		{
+			testName: "Source must exist",
+			changes: []folder.Change{
+				{Kind: folder.ChangeCreate, OrgId: validOrgId, Path: "delta"},
+				{Kind: folder.ChangeMove, OrgId: validOrgId, Path: "charlie.bravo", NewPath: "delta.bravo"},
+			},
+			index: 1,
+			err:   folder.ErrSourceNotFound,
+		},
+		{
+			testName: "Destination must not exist",
+			changes: []folder.Change{
+				{Kind: folder.ChangeRename, OrgId: validOrgId, Path: "alpha", NewPath: "charlie"},
+			},
+			err: folder.ErrFolderExists,
+		},
+		{
+			testName: "Parent of a created folder must exist",
+			changes: []folder.Change{
+				{Kind: folder.ChangeCreate, OrgId: otherOrgId, Path: "alpha.delta"},
+			},
+			err: folder.ErrFolderNotFound,
+		},
+		{
+			testName: "Moves keep the folder's name",
+			changes: []folder.Change{
+				{Kind: folder.ChangeMove, OrgId: validOrgId, Path: "alpha.bravo", NewPath: "charlie.delta"},
+			},
+			err: folder.ErrInvalidChange,
+		},
+		{
+			testName: "Renames keep the folder's parent",
+			changes: []folder.Change{
+				{Kind: folder.ChangeRename, OrgId: validOrgId, Path: "alpha.bravo", NewPath: "charlie.bravo"},
+			},
+			err: folder.ErrInvalidChange,
+		},
+		{
+			testName: "Unknown kind",
+			changes: []folder.Change{
+				{Kind: "copy", OrgId: validOrgId, Path: "alpha", NewPath: "delta"},
+			},
+			err: folder.ErrInvalidChange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			d := folder.NewDriver(folders)
+			err := folder.ApplyPatch(d, folder.ChangeSet{Changes: tt.changes})
+			if tt.err == nil {
+				require.NoError(t, err)
+				assert.ElementsMatch(t, tt.want, append(d.GetFoldersByOrgID(validOrgId), d.GetFoldersByOrgID(otherOrgId)...))
+				return
+			}
+
+			assert.ErrorIs(t, err, tt.err)
+			var patchErr *folder.PatchError
+			require.ErrorAs(t, err, &patchErr)
+			assert.Equal(t, tt.index, patchErr.Index)
+			assert.Equal(t, folders, append(d.GetFoldersByOrgID(validOrgId), d.GetFoldersByOrgID(otherOrgId)...),
+				"A patch which cannot be made changes nothing")
+		})
+	}
+}
+
+func Test_folder_ReadPatch(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+
+	patch := folder.ChangeSet{Changes: []folder.Change{
+		{Kind: folder.ChangeCreate, OrgId: validOrgId, Path: "delta"},
+		{Kind: folder.ChangeMove, OrgId: validOrgId, Path: "alpha.bravo", NewPath: "delta.bravo"},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, folder.WritePatch(&buf, patch))
+	read, err := folder.ReadPatch(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, patch, read)
+
+	_, err = folder.ReadPatch(strings.NewReader(`{"changes": [
+		{"kind": "create", "org_id": "c59cc5c1-9b81-4d00-95e3-22c6efdaf134", "path": "delta"},
+		{"kind": "delete", "org_id": "c59cc5c1-9b81-4d00-95e3-22c6efdaf134", "path": "alpha..bravo"}
+	]}`))
+	assert.ErrorIs(t, err, folder.ErrInvalidPath)
+	var patchErr *folder.PatchError
+	require.ErrorAs(t, err, &patchErr)
+	assert.Equal(t, 1, patchErr.Index)
+
+	_, err = folder.ReadPatch(strings.NewReader(`{"changes": [], "extra": true}`))
+	assert.Error(t, err, "Unknown fields are refused")
+}
@@ -0,0 +1,199 @@
+package folder_test
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Properties every driver keeps, checked over random trees and random sequences of moves
+
+var propertyOrgs = [...]uuid.UUID{
+	uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134"),
+	uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3"),
+}
+
+var propertyDrivers = [...]struct {
+	testName  string
+	newDriver func([]folder.Folder) folder.IObservableDriver
+}{
+	{testName: "Driver", newDriver: folder.NewDriver},
+	{testName: "Concurrent driver", newDriver: folder.NewConcurrentDriver},
+	{testName: "Sharded driver", newDriver: folder.NewShardedDriver},
+}
+
+// randomTree makes size folders shared between the property orgs, each beneath a random earlier folder
+// of its org or at the root. Folders are named f0, f1 and so on, so many names begin with another's
+// label as f1 and f10 do. Names are unique, unless shared, when each org numbers its folders from f0.
+func randomTree(rng *rand.Rand, size int, shared bool) []folder.Folder {
+	folders := []folder.Folder{}
+	paths := map[uuid.UUID][]string{}
+	for i := 0; i < size; i++ {
+		orgID := propertyOrgs[rng.Intn(len(propertyOrgs))]
+
+		var name string = fmt.Sprintf("f%d", i)
+		if shared {
+			name = fmt.Sprintf("f%d", len(paths[orgID]))
+		}
+
+		var path string = name
+		if n := len(paths[orgID]); n > 0 && rng.Intn(4) > 0 {
+			path = paths[orgID][rng.Intn(n)] + "." + name
+		}
+		paths[orgID] = append(paths[orgID], path)
+		folders = append(folders, folder.Folder{Name: name, OrgId: orgID, Paths: path})
+	}
+	return folders
+}
+
+// driverFolders returns the folders held by a driver, by org
+func driverFolders(d folder.IDriver) map[uuid.UUID][]folder.Folder {
+	folders := map[uuid.UUID][]folder.Folder{}
+	for _, orgID := range propertyOrgs {
+		folders[orgID] = d.GetFoldersByOrgID(orgID)
+	}
+	return folders
+}
+
+// checkMove checks moving the folder called src beneath the folder called dst keeps every property.
+// A move which fails must change nothing, and one which succeeds must leave well-formed trees holding
+// the same folders, by name and org, with src and its descendants beneath dst. Moving src back restores
+// the folders as they were.
+func checkMove(t *testing.T, d folder.IObservableDriver, src string, dst string) {
+	t.Helper()
+
+	before := driverFolders(d)
+	_, err := d.MoveFolder(src, dst)
+	after := driverFolders(d)
+	if err != nil {
+		assert.Equal(t, before, after, "A failed move of %s to %s changes nothing", src, dst)
+		return
+	}
+
+	all := []folder.Folder{}
+	for _, orgID := range propertyOrgs {
+		all = append(all, after[orgID]...)
+		require.Len(t, after[orgID], len(before[orgID]), "Orgs keep their folders")
+	}
+	require.Empty(t, folder.Validate(all), "Every folder's parent exists, and names match last labels")
+
+	// Which folder is moved when names repeat is up to the driver, so find the one that was
+	var moved *folder.Folder
+	var newPath string
+	for _, orgID := range propertyOrgs {
+		for i, f := range before[orgID] {
+			if f.Name == src && after[orgID][i].Paths != f.Paths {
+				moved, newPath = &before[orgID][i], after[orgID][i].Paths
+			}
+		}
+	}
+	if moved == nil {
+		assert.Equal(t, before, after, "A move of %s to where it is changes nothing", src)
+		return
+	}
+
+	var parent, dstPath string = "", newPath[:strings.LastIndex(newPath, ".")]
+	if i := strings.LastIndex(moved.Paths, "."); i >= 0 {
+		parent = moved.Paths[:i]
+	}
+	require.Equal(t, dst, dstPath[strings.LastIndex(dstPath, ".")+1:], "%s moves beneath %s", src, dst)
+
+	for _, orgID := range propertyOrgs {
+		for i, f := range before[orgID] {
+			var got folder.Folder = after[orgID][i]
+			require.Equal(t, f.Name, got.Name, "Folders keep their order and names")
+			require.Equal(t, f.OrgId, got.OrgId, "Folders keep their org")
+
+			if orgID == moved.OrgId && (f.Paths == moved.Paths || strings.HasPrefix(f.Paths, moved.Paths+".")) {
+				require.Equal(t, newPath+strings.TrimPrefix(f.Paths, moved.Paths), got.Paths, "%s moves with %s", f.Paths, src)
+			} else {
+				require.Equal(t, f.Paths, got.Paths, "%s stays put when moving %s", f.Paths, src)
+			}
+		}
+	}
+
+	_, err = d.MoveFolderByPath(moved.OrgId, newPath, parent)
+	require.NoError(t, err)
+	require.Equal(t, before, driverFolders(d), "Moving %s back restores the folders", src)
+}
+
+func Test_folder_MoveFolder_Properties(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range propertyDrivers {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			rng := rand.New(rand.NewSource(1))
+			for round := 0; round < 300; round++ {
+				var size int = 1 + rng.Intn(30)
+				d := tt.newDriver(randomTree(rng, size, round%3 == 0))
+				for moves := 0; moves < 10; moves++ {
+					checkMove(t, d, fmt.Sprintf("f%d", rng.Intn(size)), fmt.Sprintf("f%d", rng.Intn(size)))
+				}
+			}
+		})
+	}
+}
+
+func Fuzz_folder_MoveFolder(f *testing.F) {
+	f.Add(int64(1), uint8(12), uint8(1), uint8(10), false)
+	f.Add(int64(2), uint8(30), uint8(3), uint8(0), true)
+	f.Add(int64(3), uint8(1), uint8(0), uint8(0), false)
+
+	f.Fuzz(func(t *testing.T, seed int64, size uint8, src uint8, dst uint8, shared bool) {
+		var n int = int(size)%40 + 1
+		folders := randomTree(rand.New(rand.NewSource(seed)), n, shared)
+		for _, tt := range propertyDrivers {
+			checkMove(t, tt.newDriver(folders), fmt.Sprintf("f%d", int(src)%n), fmt.Sprintf("f%d", int(dst)%n))
+		}
+	})
+}
+
+func Fuzz_folder_MoveFolderByPath(f *testing.F) {
+	f.Add(int64(1), uint8(12), []byte{1, 10, 4, 7, 0, 3})
+	f.Add(int64(2), uint8(30), []byte{})
+
+	// Each pair of bytes picks the folders whose paths are moved and moved to, where a folder
+	// past the end of the org moves to the root
+	f.Fuzz(func(t *testing.T, seed int64, size uint8, moves []byte) {
+		folders := randomTree(rand.New(rand.NewSource(seed)), int(size)%40+1, false)
+		d := folder.NewDriver(folders)
+		before := driverFolders(d)
+
+		for i := 0; i+1 < len(moves); i += 2 {
+			state := driverFolders(d)
+			orgID := propertyOrgs[int(moves[i])%len(propertyOrgs)]
+			org := state[orgID]
+			if len(org) == 0 {
+				continue
+			}
+
+			var src string = org[int(moves[i])/len(propertyOrgs)%len(org)].Paths
+			var dst string = ""
+			if j := int(moves[i+1]) % (len(org) + 1); j < len(org) {
+				dst = org[j].Paths
+			}
+
+			if _, err := d.MoveFolderByPath(orgID, src, dst); err != nil {
+				require.Equal(t, state, driverFolders(d), "A failed move of %s to %q changes nothing", src, dst)
+				continue
+			}
+
+			after := driverFolders(d)
+			require.Empty(t, folder.Validate(append(append([]folder.Folder{}, after[propertyOrgs[0]]...), after[propertyOrgs[1]]...)))
+			for _, orgID := range propertyOrgs {
+				require.Len(t, after[orgID], len(before[orgID]), "Orgs keep their folders")
+				for j, f := range before[orgID] {
+					require.Equal(t, f.Name, after[orgID][j].Name, "Folders keep their names")
+				}
+			}
+		}
+	})
+}
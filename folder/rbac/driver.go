@@ -0,0 +1,154 @@
+package rbac
+
+import (
+	"fmt"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+)
+
+// Driver is a folder.IDriver acting for one principal, which hides the folders the principal cannot view
+// and refuses moves it cannot make. Hidden folders behave as though they do not exist, so their names never leak.
+//
+// Moving a folder needs the editor role on both the folder and its destination.
+// Grants on a moved folder and its descendants follow it to its new path.
+type Driver struct {
+	driver    folder.IStatefulDriver
+	policy    *Policy
+	principal string
+}
+
+// NewDriver creates a driver acting for principal over d, as allowed by policy.
+// d moves folders by path, so a move reaches only the folder the principal was checked against.
+func NewDriver(d folder.IStatefulDriver, policy *Policy, principal string) *Driver {
+	return &Driver{driver: d, policy: policy, principal: principal}
+}
+
+// can checks if the principal holds at least role on a folder
+func (d *Driver) can(f folder.Folder, role Role) bool {
+	return d.policy.Role(d.principal, f.OrgId, f.Paths) >= role
+}
+
+// visible filters folders to those the principal can view
+func (d *Driver) visible(folders []folder.Folder) []folder.Folder {
+	result := []folder.Folder{}
+	for _, f := range folders {
+		if d.can(f, Viewer) {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// find returns the first folder called name within an organisation which the principal can view, if any,
+// so a hidden folder of the same name never stands in for it
+func (d *Driver) find(orgID uuid.UUID, name string) (folder.Folder, bool) {
+	for _, f := range d.driver.GetFoldersByOrgID(orgID) {
+		if f.Name == name && d.can(f, Viewer) {
+			return f, true
+		}
+	}
+	return folder.Folder{}, false
+}
+
+func (d *Driver) GetFoldersByOrgID(orgID uuid.UUID) []folder.Folder {
+	return d.visible(d.driver.GetFoldersByOrgID(orgID))
+}
+
+// GetAllChildFolders returns the children the principal can view, so where several folders are called name,
+// only the children of those it can view. It fails with folder.ErrFolderNotFound if it can view none of them.
+func (d *Driver) GetAllChildFolders(orgID uuid.UUID, name string) ([]folder.Folder, error) {
+	var named int = 0
+	shown := []string{}
+	for _, f := range d.driver.GetFoldersByOrgID(orgID) {
+		if f.Name == name {
+			named++
+			if d.can(f, Viewer) {
+				shown = append(shown, f.Paths)
+			}
+		}
+	}
+	if named > 0 && len(shown) == 0 {
+		return []folder.Folder{}, folder.ErrFolderNotFound
+	}
+
+	children, err := d.driver.GetAllChildFolders(orgID, name)
+	if err != nil {
+		return []folder.Folder{}, err
+	}
+
+	// A child the principal can view through its own grant is still hidden if it is beneath a hidden folder of the name
+	result := []folder.Folder{}
+	for _, c := range d.visible(children) {
+		for _, p := range shown {
+			if folder.IsDescendantPath(p, c.Paths) {
+				result = append(result, c)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// MoveFolder moves the first folder called name the principal can view, looking for it in the organisations
+// the principal holds grants in, beneath the first folder called dst it can view in the same organisation.
+// It returns the folders the principal can view afterwards.
+// The folders checked are moved by path, so folders of the same names in other organisations are never moved instead.
+func (d *Driver) MoveFolder(name string, dst string) ([]folder.Folder, error) {
+	var orgs []uuid.UUID = d.policy.orgs(d.principal)
+
+	var src folder.Folder
+	var found bool = false
+	for _, orgID := range orgs {
+		if src, found = d.find(orgID, name); found {
+			break
+		}
+	}
+	if !found {
+		return []folder.Folder{}, folder.ErrSourceNotFound
+	}
+	if !d.can(src, Editor) {
+		return []folder.Folder{}, fmt.Errorf("moving %q: %w", src.Paths, ErrForbidden)
+	}
+
+	dstFolder, found := d.find(src.OrgId, dst)
+	if !found {
+		for _, orgID := range orgs {
+			if _, found := d.find(orgID, dst); found && orgID != src.OrgId {
+				return []folder.Folder{}, folder.ErrMoveToOtherOrg
+			}
+		}
+		return []folder.Folder{}, folder.ErrDestinationNotFound
+	}
+	if !d.can(dstFolder, Editor) {
+		return []folder.Folder{}, fmt.Errorf("moving to %q: %w", dstFolder.Paths, ErrForbidden)
+	}
+
+	folders, err := d.driver.MoveFolderByPath(src.OrgId, src.Paths, dstFolder.Paths)
+	if err != nil {
+		return []folder.Folder{}, err
+	}
+
+	var newPath string = dstFolder.Paths + "." + src.Name
+	if newPath != src.Paths {
+		d.policy.rebase(src.OrgId, src.Paths, newPath)
+	}
+	return d.visible(folders), nil
+}
+
+// Grant makes a grant, which needs the admin role on the folder granted on
+func (d *Driver) Grant(g Grant) error {
+	if d.policy.Role(d.principal, g.OrgID, g.Path) < Admin {
+		return fmt.Errorf("granting on %q: %w", g.Path, ErrForbidden)
+	}
+	return d.policy.Grant(g)
+}
+
+// Revoke removes a principal's grant on path, which needs the admin role on that folder
+func (d *Driver) Revoke(principal string, orgID uuid.UUID, path string) error {
+	if d.policy.Role(d.principal, orgID, path) < Admin {
+		return fmt.Errorf("revoking on %q: %w", path, ErrForbidden)
+	}
+	d.policy.Revoke(principal, orgID, path)
+	return nil
+}
@@ -0,0 +1,371 @@
+package rbac_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/folder/drivertest"
+	"github.com/georgechieng-sc/interns-2022/folder/rbac"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rbac_GetFoldersByOrgID(t *testing.T) {
+	t.Parallel()
+
+	sampleFolders := drivertest.MoveScenario()
+
+	policy, err := rbac.NewPolicy(
+		rbac.Grant{Principal: "ann", OrgID: drivertest.OrgID, Path: "alpha.delta", Role: rbac.Viewer},
+		rbac.Grant{Principal: "ann", OrgID: drivertest.OrgID, Path: "golf", Role: rbac.Editor},
+		rbac.Grant{Principal: "bob", OrgID: drivertest.OtherOrgID, Path: "", Role: rbac.Admin},
+	)
+	require.NoError(t, err)
+	d := rbac.NewDriver(drivertest.NewMoveDriver(), policy, "ann")
+
+	assert.Equal(t, []folder.Folder{sampleFolders[3], sampleFolders[4], sampleFolders[6]}, d.GetFoldersByOrgID(drivertest.OrgID))
+	assert.Equal(t, []folder.Folder{}, d.GetFoldersByOrgID(drivertest.OtherOrgID))
+}
+
+func Test_rbac_GetAllChildFolders(t *testing.T) {
+	t.Parallel()
+
+	folders := []folder.Folder{
+		{Name: "fold", OrgId: drivertest.OrgID, Paths: "fold"},
+		{Name: "c1", OrgId: drivertest.OrgID, Paths: "fold.c1"},
+		{Name: "child-path-1", OrgId: drivertest.OrgID, Paths: "fold.c1.child-path-1"},
+		{Name: "new-fold", OrgId: drivertest.OrgID, Paths: "new-fold"},
+		{Name: "c1", OrgId: drivertest.OrgID, Paths: "new-fold.c1"},
+		{Name: "child-path-2", OrgId: drivertest.OrgID, Paths: "new-fold.c1.child-path-2"},
+	}
+
+	tests := [...]struct {
+		testName string
+		grants   []rbac.Grant
+		name     string
+		want     []folder.Folder
+		err      error
+	}{
+		{
+			testName: "Granted on the whole org",
+			grants:   []rbac.Grant{{Principal: "ann", OrgID: drivertest.OrgID, Role: rbac.Viewer}},
+			name:     "c1",
+			want:     []folder.Folder{folders[2], folders[5]},
+		},
+		{
+			testName: "Children of hidden folders of the name are hidden",
+			grants: []rbac.Grant{
+				{Principal: "ann", OrgID: drivertest.OrgID, Path: "fold", Role: rbac.Viewer},
+				{Principal: "bob", OrgID: drivertest.OrgID, Path: "new-fold", Role: rbac.Viewer},
+			},
+			name: "c1",
+			want: []folder.Folder{folders[2]},
+		},
+		{
+			testName: "Visible children of hidden folders of the name are hidden",
+			grants: []rbac.Grant{
+				{Principal: "ann", OrgID: drivertest.OrgID, Path: "fold", Role: rbac.Viewer},
+				{Principal: "ann", OrgID: drivertest.OrgID, Path: "new-fold.c1.child-path-2", Role: rbac.Viewer},
+			},
+			name: "c1",
+			want: []folder.Folder{folders[2]},
+		},
+		{
+			testName: "Granted on the folder itself",
+			grants:   []rbac.Grant{{Principal: "ann", OrgID: drivertest.OrgID, Path: "new-fold.c1", Role: rbac.Viewer}},
+			name:     "c1",
+			want:     []folder.Folder{folders[5]},
+		},
+		{
+			testName: "Hidden folder does not exist",
+			grants:   []rbac.Grant{{Principal: "ann", OrgID: drivertest.OrgID, Path: "fold.c1", Role: rbac.Admin}},
+			name:     "fold",
+			err:      folder.ErrFolderNotFound,
+		},
+		{
+			testName: "Another principal's grant is not used",
+			grants:   []rbac.Grant{{Principal: "bob", OrgID: drivertest.OrgID, Role: rbac.Admin}},
+			name:     "fold",
+			err:      folder.ErrFolderNotFound,
+		},
+		{
+			testName: "Missing folder does not exist",
+			grants:   []rbac.Grant{{Principal: "ann", OrgID: drivertest.OrgID, Role: rbac.Viewer}},
+			name:     "invalid_folder",
+			err:      folder.ErrFolderNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			policy, err := rbac.NewPolicy(tt.grants...)
+			require.NoError(t, err)
+			d := rbac.NewDriver(folder.NewDriver(folders), policy, "ann")
+
+			children, err := d.GetAllChildFolders(drivertest.OrgID, tt.name)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, children)
+		})
+	}
+}
+
+func Test_rbac_MoveFolder(t *testing.T) {
+	t.Parallel()
+
+	tests := [...]struct {
+		testName string
+		grants   []rbac.Grant
+		src      string
+		dst      string
+		want     []folder.Folder
+		err      error
+	}{
+		{
+			testName: "Editor of source and destination",
+			grants: []rbac.Grant{
+				{Principal: "ann", OrgID: drivertest.OrgID, Path: "alpha.bravo", Role: rbac.Editor},
+				{Principal: "ann", OrgID: drivertest.OrgID, Path: "golf", Role: rbac.Admin},
+			},
+			src: "bravo",
+			dst: "golf",
+			want: []folder.Folder{
+				{Name: "bravo", OrgId: drivertest.OrgID, Paths: "golf.bravo"},
+				{Name: "charlie", OrgId: drivertest.OrgID, Paths: "golf.bravo.charlie"},
+				{Name: "golf", OrgId: drivertest.OrgID, Paths: "golf"},
+			},
+		},
+		{
+			testName: "Viewer of the source",
+			grants: []rbac.Grant{
+				{Principal: "ann", OrgID: drivertest.OrgID, Path: "alpha", Role: rbac.Viewer},
+				{Principal: "ann", OrgID: drivertest.OrgID, Path: "golf", Role: rbac.Editor},
+			},
+			src: "bravo",
+			dst: "golf",
+			err: rbac.ErrForbidden,
+		},
+		{
+			testName: "Viewer of the destination",
+			grants: []rbac.Grant{
+				{Principal: "ann", OrgID: drivertest.OrgID, Path: "alpha", Role: rbac.Viewer},
+				{Principal: "ann", OrgID: drivertest.OrgID, Path: "alpha.bravo", Role: rbac.Editor},
+			},
+			src: "bravo",
+			dst: "delta",
+			err: rbac.ErrForbidden,
+		},
+		{
+			testName: "Hidden source does not exist",
+			grants:   []rbac.Grant{{Principal: "ann", OrgID: drivertest.OrgID, Path: "golf", Role: rbac.Editor}},
+			src:      "bravo",
+			dst:      "golf",
+			err:      folder.ErrSourceNotFound,
+		},
+		{
+			testName: "Hidden destination does not exist",
+			grants:   []rbac.Grant{{Principal: "ann", OrgID: drivertest.OrgID, Path: "alpha.bravo", Role: rbac.Editor}},
+			src:      "bravo",
+			dst:      "golf",
+			err:      folder.ErrDestinationNotFound,
+		},
+		{
+			testName: "Hidden destination in another org does not exist",
+			grants:   []rbac.Grant{{Principal: "ann", OrgID: drivertest.OrgID, Role: rbac.Admin}},
+			src:      "bravo",
+			dst:      "foxtrot",
+			err:      folder.ErrDestinationNotFound,
+		},
+		{
+			testName: "Visible destination in another org",
+			grants: []rbac.Grant{
+				{Principal: "ann", OrgID: drivertest.OrgID, Role: rbac.Admin},
+				{Principal: "ann", OrgID: drivertest.OtherOrgID, Role: rbac.Viewer},
+			},
+			src: "bravo",
+			dst: "foxtrot",
+			err: folder.ErrMoveToOtherOrg,
+		},
+		{
+			testName: "Errors of the underlying driver are kept",
+			grants:   []rbac.Grant{{Principal: "ann", OrgID: drivertest.OrgID, Role: rbac.Editor}},
+			src:      "bravo",
+			dst:      "charlie",
+			err:      folder.ErrMoveToChild,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			policy, err := rbac.NewPolicy(tt.grants...)
+			require.NoError(t, err)
+			d := rbac.NewDriver(drivertest.NewMoveDriver(), policy, "ann")
+			folders, err := d.MoveFolder(tt.src, tt.dst)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, folders)
+		})
+	}
+}
+
+func Test_rbac_MoveFolder_GrantsFollow(t *testing.T) {
+	t.Parallel()
+
+	policy, err := rbac.NewPolicy(
+		rbac.Grant{Principal: "ann", OrgID: drivertest.OrgID, Role: rbac.Editor},
+		rbac.Grant{Principal: "bob", OrgID: drivertest.OrgID, Path: "alpha.bravo", Role: rbac.Viewer},
+		rbac.Grant{Principal: "bob", OrgID: drivertest.OrgID, Path: "alpha.bravo.charlie", Role: rbac.Editor},
+		rbac.Grant{Principal: "bob", OrgID: drivertest.OrgID, Path: "alpha.delta", Role: rbac.Viewer},
+	)
+	require.NoError(t, err)
+	d := rbac.NewDriver(drivertest.NewMoveDriver(), policy, "ann")
+
+	_, err = d.MoveFolder("bravo", "golf")
+	require.NoError(t, err)
+	assert.Equal(t, []rbac.Grant{
+		{Principal: "bob", OrgID: drivertest.OrgID, Path: "golf.bravo", Role: rbac.Viewer},
+		{Principal: "bob", OrgID: drivertest.OrgID, Path: "golf.bravo.charlie", Role: rbac.Editor},
+		{Principal: "bob", OrgID: drivertest.OrgID, Path: "alpha.delta", Role: rbac.Viewer},
+	}, policy.Grants("bob"))
+	assert.Equal(t, rbac.None, policy.Role("bob", drivertest.OrgID, "alpha.bravo"))
+}
+
+func Test_rbac_MoveFolder_DuplicateNames(t *testing.T) {
+	t.Parallel()
+
+	// The org the principal holds no grants in comes first, so a move by name alone would reach its folders
+	var hiddenOrgId = uuid.FromStringOrNil("38b9879b-f73b-4b0e-b9d9-4fc4c23643a7")
+	hidden := []folder.Folder{
+		{Name: "bravo", OrgId: hiddenOrgId, Paths: "bravo"},
+		{Name: "golf", OrgId: hiddenOrgId, Paths: "golf"},
+	}
+	inner := folder.NewDriver(append(append([]folder.Folder{}, hidden...),
+		folder.Folder{Name: "bravo", OrgId: drivertest.OrgID, Paths: "bravo"},
+		folder.Folder{Name: "golf", OrgId: drivertest.OrgID, Paths: "golf"},
+	))
+
+	policy, err := rbac.NewPolicy(
+		rbac.Grant{Principal: "ann", OrgID: drivertest.OrgID, Role: rbac.Editor},
+		rbac.Grant{Principal: "bob", OrgID: drivertest.OrgID, Path: "bravo", Role: rbac.Viewer},
+	)
+	require.NoError(t, err)
+	d := rbac.NewDriver(inner, policy, "ann")
+
+	folders, err := d.MoveFolder("bravo", "golf")
+	require.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "bravo", OrgId: drivertest.OrgID, Paths: "golf.bravo"},
+		{Name: "golf", OrgId: drivertest.OrgID, Paths: "golf"},
+	}, folders)
+	assert.Equal(t, hidden, inner.GetFoldersByOrgID(hiddenOrgId), "Folders of the same names elsewhere stay put")
+	assert.Equal(t, rbac.Viewer, policy.Role("bob", drivertest.OrgID, "golf.bravo"))
+}
+
+func Test_rbac_MoveFolder_HiddenNames(t *testing.T) {
+	t.Parallel()
+
+	var hiddenOrgId = uuid.FromStringOrNil("38b9879b-f73b-4b0e-b9d9-4fc4c23643a7")
+
+	tests := [...]struct {
+		testName string
+		folders  []folder.Folder
+		grants   []rbac.Grant
+		want     []folder.Folder
+	}{
+		{
+			testName: "Hidden folders ahead in the same org are passed over",
+			folders: []folder.Folder{
+				{Name: "h", OrgId: drivertest.OrgID, Paths: "h"},
+				{Name: "bravo", OrgId: drivertest.OrgID, Paths: "h.bravo"},
+				{Name: "golf", OrgId: drivertest.OrgID, Paths: "h.golf"},
+				{Name: "v", OrgId: drivertest.OrgID, Paths: "v"},
+				{Name: "bravo", OrgId: drivertest.OrgID, Paths: "v.bravo"},
+				{Name: "golf", OrgId: drivertest.OrgID, Paths: "v.golf"},
+			},
+			grants: []rbac.Grant{{Principal: "ann", OrgID: drivertest.OrgID, Path: "v", Role: rbac.Editor}},
+			want: []folder.Folder{
+				{Name: "v", OrgId: drivertest.OrgID, Paths: "v"},
+				{Name: "bravo", OrgId: drivertest.OrgID, Paths: "v.golf.bravo"},
+				{Name: "golf", OrgId: drivertest.OrgID, Paths: "v.golf"},
+			},
+		},
+		{
+			testName: "Hidden folders ahead in another org are passed over",
+			folders: []folder.Folder{
+				{Name: "alpha", OrgId: hiddenOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: hiddenOrgId, Paths: "bravo"},
+				{Name: "bravo", OrgId: drivertest.OrgID, Paths: "bravo"},
+				{Name: "golf", OrgId: drivertest.OrgID, Paths: "golf"},
+			},
+			grants: []rbac.Grant{
+				{Principal: "ann", OrgID: hiddenOrgId, Path: "alpha", Role: rbac.Editor},
+				{Principal: "ann", OrgID: drivertest.OrgID, Role: rbac.Editor},
+			},
+			want: []folder.Folder{
+				{Name: "alpha", OrgId: hiddenOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: drivertest.OrgID, Paths: "golf.bravo"},
+				{Name: "golf", OrgId: drivertest.OrgID, Paths: "golf"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			policy, err := rbac.NewPolicy(tt.grants...)
+			require.NoError(t, err)
+			d := rbac.NewDriver(folder.NewDriver(tt.folders), policy, "ann")
+
+			folders, err := d.MoveFolder("bravo", "golf")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, folders)
+		})
+	}
+}
+
+func Test_rbac_Grant(t *testing.T) {
+	t.Parallel()
+
+	policy, err := rbac.NewPolicy(
+		rbac.Grant{Principal: "ann", OrgID: drivertest.OrgID, Path: "alpha", Role: rbac.Admin},
+		rbac.Grant{Principal: "ann", OrgID: drivertest.OrgID, Path: "golf", Role: rbac.Editor},
+	)
+	require.NoError(t, err)
+	d := rbac.NewDriver(drivertest.NewMoveDriver(), policy, "ann")
+
+	require.NoError(t, d.Grant(rbac.Grant{Principal: "bob", OrgID: drivertest.OrgID, Path: "alpha.bravo", Role: rbac.Editor}))
+	assert.Equal(t, rbac.Editor, policy.Role("bob", drivertest.OrgID, "alpha.bravo.charlie"))
+	assert.ErrorIs(t, d.Grant(rbac.Grant{Principal: "bob", OrgID: drivertest.OrgID, Path: "golf", Role: rbac.Viewer}), rbac.ErrForbidden)
+	assert.ErrorIs(t, d.Grant(rbac.Grant{Principal: "bob", OrgID: drivertest.OrgID, Role: rbac.Viewer}), rbac.ErrForbidden)
+
+	assert.ErrorIs(t, d.Revoke("ann", drivertest.OrgID, "golf"), rbac.ErrForbidden)
+	require.NoError(t, d.Revoke("bob", drivertest.OrgID, "alpha.bravo"))
+	assert.Equal(t, rbac.None, policy.Role("bob", drivertest.OrgID, "alpha.bravo"))
+}
+
+func Test_rbac_Conformance(t *testing.T) {
+	t.Parallel()
+
+	// An admin of every org sees and may do everything, so is held to the same behaviour as any driver
+	policy, err := rbac.NewPolicy(
+		rbac.Grant{Principal: "ann", OrgID: drivertest.OrgID, Role: rbac.Admin},
+		rbac.Grant{Principal: "ann", OrgID: drivertest.OtherOrgID, Role: rbac.Admin},
+	)
+	require.NoError(t, err)
+
+	drivertest.RunConformance(t, func(folders []folder.Folder) folder.IDriver {
+		return rbac.NewDriver(folder.NewDriver(folders), policy, "ann")
+	})
+}
@@ -0,0 +1,73 @@
+package rbac_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder/drivertest"
+	"github.com/georgechieng-sc/interns-2022/folder/rbac"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rbac_Policy_Role(t *testing.T) {
+	t.Parallel()
+
+	policy, err := rbac.NewPolicy(
+		rbac.Grant{Principal: "ann", OrgID: drivertest.OrgID, Path: "alpha", Role: rbac.Viewer},
+		rbac.Grant{Principal: "ann", OrgID: drivertest.OrgID, Path: "alpha.bravo", Role: rbac.Editor},
+		rbac.Grant{Principal: "ann", OrgID: drivertest.OrgID, Path: "alpha.bravo.charlie", Role: rbac.Viewer},
+		rbac.Grant{Principal: "bob", OrgID: drivertest.OtherOrgID, Path: "", Role: rbac.Admin},
+	)
+	require.NoError(t, err)
+
+	tests := [...]struct {
+		testName  string
+		principal string
+		orgID     uuid.UUID
+		path      string
+		want      rbac.Role
+	}{
+		{testName: "Granted on the folder", principal: "ann", orgID: drivertest.OrgID, path: "alpha", want: rbac.Viewer},
+		{testName: "Inherited from the parent", principal: "ann", orgID: drivertest.OrgID, path: "alpha.delta", want: rbac.Viewer},
+		{testName: "Highest of the folder's and its ancestors'", principal: "ann", orgID: drivertest.OrgID, path: "alpha.bravo.charlie", want: rbac.Editor},
+		{testName: "Not granted on a sibling", principal: "ann", orgID: drivertest.OrgID, path: "echo", want: rbac.None},
+		{testName: "Not granted on a folder whose name begins with the granted one's", principal: "ann", orgID: drivertest.OrgID, path: "alphabet", want: rbac.None},
+		{testName: "Not granted in another org", principal: "ann", orgID: drivertest.OtherOrgID, path: "alpha", want: rbac.None},
+		{testName: "Granted on the whole org", principal: "bob", orgID: drivertest.OtherOrgID, path: "foxtrot.golf", want: rbac.Admin},
+		{testName: "Unknown principal", principal: "eve", orgID: drivertest.OrgID, path: "alpha", want: rbac.None},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, policy.Role(tt.principal, tt.orgID, tt.path))
+		})
+	}
+}
+
+func Test_rbac_Policy_GrantRevoke(t *testing.T) {
+	t.Parallel()
+
+	policy, err := rbac.NewPolicy()
+	require.NoError(t, err)
+
+	require.NoError(t, policy.Grant(rbac.Grant{Principal: "ann", OrgID: drivertest.OrgID, Path: "alpha", Role: rbac.Viewer}))
+	require.NoError(t, policy.Grant(rbac.Grant{Principal: "ann", OrgID: drivertest.OrgID, Path: "alpha", Role: rbac.Editor}))
+	assert.Equal(t, []rbac.Grant{
+		{Principal: "ann", OrgID: drivertest.OrgID, Path: "alpha", Role: rbac.Editor},
+	}, policy.Grants("ann"), "A grant on the same path replaces the last")
+
+	policy.Revoke("ann", drivertest.OrgID, "alpha.bravo")
+	assert.Equal(t, rbac.Editor, policy.Role("ann", drivertest.OrgID, "alpha.bravo"), "Revoking beneath a grant leaves it in place")
+
+	policy.Revoke("ann", drivertest.OrgID, "alpha")
+	assert.Equal(t, rbac.None, policy.Role("ann", drivertest.OrgID, "alpha"))
+	assert.Empty(t, policy.Grants("ann"))
+
+	assert.ErrorIs(t, policy.Grant(rbac.Grant{OrgID: drivertest.OrgID, Role: rbac.Viewer}), rbac.ErrInvalidGrant)
+	assert.ErrorIs(t, policy.Grant(rbac.Grant{Principal: "ann", OrgID: drivertest.OrgID}), rbac.ErrInvalidGrant)
+	_, err = rbac.NewPolicy(rbac.Grant{Principal: "ann", Role: rbac.Admin + 1})
+	assert.ErrorIs(t, err, rbac.ErrInvalidGrant)
+}
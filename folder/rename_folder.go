@@ -0,0 +1,33 @@
+package folder
+
+import "github.com/gofrs/uuid"
+
+func (f *driver) RenameFolder(orgID uuid.UUID, path string, newName string) ([]Folder, error) {
+	if !isValidName(newName) {
+		return []Folder{}, ErrInvalidName
+	}
+
+	folder, found := findFolderByPath(&f.folders, orgID, path)
+	if !found {
+		return []Folder{}, ErrFolderNotFound
+	}
+
+	if folder.Name == newName {
+		return f.folders, nil
+	}
+
+	var newPath string = JoinPath(ParentPath(path), newName)
+	if _, exists := findFolderByPath(&f.folders, orgID, newPath); exists {
+		return []Folder{}, ErrFolderExists
+	}
+
+	if err := f.checkMove(folder, newPath); err != nil {
+		return []Folder{}, err
+	}
+
+	var before []Folder = f.folders
+	f.folders = rebaseFolders(&f.folders, orgID, path, newPath)
+	f.bump(orgID)
+	f.publishChanges(before, f.folders)
+	return f.folders, nil
+}
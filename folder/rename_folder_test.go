@@ -0,0 +1,107 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_RenameFolder(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+	tests := [...]struct {
+		testName string
+		path     string
+		newName  string
+		folders  []folder.Folder
+		want     []folder.Folder
+		err      error
+	}{
+		{
+			testName: "Rename folder with nested children",
+			path:     "fold.c1",
+			newName:  "renamed",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "c1", OrgId: validOrgId, Paths: "fold.c1"},
+				{Name: "c2", OrgId: validOrgId, Paths: "fold.c1.c2"},
+				{Name: "c1-sibling", OrgId: validOrgId, Paths: "fold.c1-sibling"},
+			},
+			want: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "renamed", OrgId: validOrgId, Paths: "fold.renamed"},
+				{Name: "c2", OrgId: validOrgId, Paths: "fold.renamed.c2"},
+				{Name: "c1-sibling", OrgId: validOrgId, Paths: "fold.c1-sibling"},
+			},
+		},
+		{
+			testName: "Rename root folder, other organisations untouched",
+			path:     "fold",
+			newName:  "root",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "fold", OrgId: otherOrgId, Paths: "fold"},
+			},
+			want: []folder.Folder{
+				{Name: "root", OrgId: validOrgId, Paths: "root"},
+				{Name: "fold", OrgId: otherOrgId, Paths: "fold"},
+			},
+		},
+		{
+			testName: "Rename to the same name changes nothing",
+			path:     "fold",
+			newName:  "fold",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+			},
+			want: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+			},
+		},
+		{
+			testName: "Error: Sibling with new name already exists",
+			path:     "fold.c1",
+			newName:  "c2",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "c1", OrgId: validOrgId, Paths: "fold.c1"},
+				{Name: "c2", OrgId: validOrgId, Paths: "fold.c2"},
+			},
+			err: folder.ErrFolderExists,
+		},
+		{
+			testName: "Error: Folder does not exist",
+			path:     "missing",
+			newName:  "a",
+			folders:  []folder.Folder{},
+			err:      folder.ErrFolderNotFound,
+		},
+		{
+			testName: "Error: Name contains a dot",
+			path:     "fold",
+			newName:  "a.b",
+			folders: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+			},
+			err: folder.ErrInvalidName,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			f := folder.NewDriver(tt.folders)
+			folders, err := f.RenameFolder(validOrgId, tt.path, tt.newName)
+
+			if tt.err == nil {
+				assert.NoError(t, err, tt.testName)
+				assert.Equal(t, tt.want, folders, tt.testName)
+			} else {
+				assert.ErrorIs(t, err, tt.err, tt.testName)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package folder
+
+import (
+	"sync"
+
+	"github.com/gofrs/uuid"
+)
+
+// A driver which partitions folders by organisation, each with its own lock,
+// so mutations in one organisation never wait on reads or writes in another.
+// Mutations return the folders of the organisation they changed, rather than every folder.
+type shardedDriver struct {
+	mu     sync.RWMutex
+	shards map[uuid.UUID]*shard
+	orgs   []uuid.UUID // in order of first appearance, which MoveFolder searches by
+	events *Publisher  // shared by the shards, each publishing while holding its own lock
+	limits Limits      // of every shard, each enforcing them within its organisation
+}
+
+// The folders of one organisation, with an index of their names
+type shard struct {
+	mu     sync.RWMutex
+	driver driver
+	names  map[string]int
+}
+
+// NewShardedDriver creates a driver which is safe for concurrent use, locking each organisation separately
+func NewShardedDriver(folders []Folder) IObservableDriver {
+	s := &shardedDriver{shards: map[uuid.UUID]*shard{}, events: &Publisher{}}
+
+	partitions := map[uuid.UUID][]Folder{}
+	for _, f := range folders {
+		if _, seen := partitions[f.OrgId]; !seen {
+			s.orgs = append(s.orgs, f.OrgId)
+		}
+		partitions[f.OrgId] = append(partitions[f.OrgId], f)
+	}
+
+	for orgID, orgFolders := range partitions {
+		sh := &shard{driver: driver{events: s.events}}
+		sh.store(orgFolders)
+		s.shards[orgID] = sh
+	}
+
+	return s
+}
+
+// store replaces the shard's folders and rebuilds its index, so must be called holding the shard's write lock
+func (sh *shard) store(folders []Folder) {
+	sh.driver.folders = folders
+	sh.names = make(map[string]int, len(folders))
+	for _, f := range folders {
+		sh.names[f.Name]++
+	}
+}
+
+// hasName checks if the shard holds a folder called name
+func (sh *shard) hasName(name string) bool {
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	return sh.names[name] > 0
+}
+
+// shard returns the shard of an organisation, or nil if it has no folders
+func (s *shardedDriver) shard(orgID uuid.UUID) *shard {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.shards[orgID]
+}
+
+// shardOrCreate returns the shard of an organisation, adding an empty one if it has no folders
+func (s *shardedDriver) shardOrCreate(orgID uuid.UUID) *shard {
+	if sh := s.shard(orgID); sh != nil {
+		return sh
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sh, found := s.shards[orgID]; found {
+		return sh
+	}
+	sh := &shard{driver: driver{events: s.events, limits: s.limits}, names: map[string]int{}}
+	s.shards[orgID] = sh
+	s.orgs = append(s.orgs, orgID)
+	return sh
+}
+
+func (s *shardedDriver) setLimits(limits Limits) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits = limits
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		sh.driver.setLimits(limits)
+		sh.mu.Unlock()
+	}
+}
+
+func (s *shardedDriver) getLimits() Limits {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.limits
+}
+
+// findName returns the first organisation, other than except, with a folder called name
+func (s *shardedDriver) findName(name string, except uuid.UUID) (uuid.UUID, bool) {
+	s.mu.RLock()
+	var orgs []uuid.UUID = s.orgs
+	s.mu.RUnlock()
+
+	for _, orgID := range orgs {
+		if orgID != except && s.shard(orgID).hasName(name) {
+			return orgID, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+func (s *shardedDriver) GetFoldersByOrgID(orgID uuid.UUID) []Folder {
+	sh := s.shard(orgID)
+	if sh == nil {
+		return []Folder{}
+	}
+
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	return sh.driver.GetFoldersByOrgID(orgID)
+}
+
+func (s *shardedDriver) GetAllChildFolders(orgID uuid.UUID, name string) ([]Folder, error) {
+	if sh := s.shard(orgID); sh != nil {
+		sh.mu.RLock()
+		if sh.names[name] > 0 {
+			defer sh.mu.RUnlock()
+			return sh.driver.GetAllChildFolders(orgID, name)
+		}
+		sh.mu.RUnlock()
+	}
+
+	if _, found := s.findName(name, orgID); found {
+		return []Folder{}, ErrFolderNotInOrg
+	}
+	return []Folder{}, ErrFolderNotFound
+}
+
+func (s *shardedDriver) MoveFolder(name string, dst string) ([]Folder, error) {
+	return s.moveFolder(name, dst, func(sh *shard) ([]Folder, error) {
+		return sh.mutate(sh.driver.MoveFolder(name, dst))
+	})
+}
+
+// moveFolder finds the shard holding the folder called name, and runs move within it if dst is there too
+func (s *shardedDriver) moveFolder(name string, dst string, move func(sh *shard) ([]Folder, error)) ([]Folder, error) {
+	if name == dst {
+		return []Folder{}, ErrMoveToSelf
+	}
+
+	orgID, found := s.findName(name, uuid.Nil)
+	if !found {
+		return []Folder{}, ErrSourceNotFound
+	}
+
+	sh := s.shard(orgID)
+	sh.mu.Lock()
+	if sh.names[dst] > 0 {
+		defer sh.mu.Unlock()
+		return move(sh)
+	}
+	var srcRemains bool = sh.names[name] > 0
+	sh.mu.Unlock()
+
+	if !srcRemains {
+		// Renamed or deleted since it was found
+		return []Folder{}, ErrSourceNotFound
+	}
+	if _, found := s.findName(dst, orgID); found {
+		return []Folder{}, ErrMoveToOtherOrg
+	}
+	return []Folder{}, ErrDestinationNotFound
+}
+
+func (s *shardedDriver) CreateFolder(orgID uuid.UUID, name string, parent string) ([]Folder, error) {
+	sh := s.shardOrCreate(orgID)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.mutate(sh.driver.CreateFolder(orgID, name, parent))
+}
+
+func (s *shardedDriver) MoveFolderByPath(orgID uuid.UUID, src string, dst string) ([]Folder, error) {
+	sh := s.shard(orgID)
+	if sh == nil {
+		return (&driver{}).MoveFolderByPath(orgID, src, dst)
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.mutate(sh.driver.MoveFolderByPath(orgID, src, dst))
+}
+
+func (s *shardedDriver) RenameFolder(orgID uuid.UUID, path string, newName string) ([]Folder, error) {
+	sh := s.shard(orgID)
+	if sh == nil {
+		return (&driver{}).RenameFolder(orgID, path, newName)
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.mutate(sh.driver.RenameFolder(orgID, path, newName))
+}
+
+func (s *shardedDriver) DeleteFolder(orgID uuid.UUID, path string) ([]Folder, error) {
+	sh := s.shard(orgID)
+	if sh == nil {
+		return (&driver{}).DeleteFolder(orgID, path)
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.mutate(sh.driver.DeleteFolder(orgID, path))
+}
+
+// mutate rebuilds the index after a successful mutation, so must be called holding the shard's write lock
+func (sh *shard) mutate(result []Folder, err error) ([]Folder, error) {
+	if err != nil {
+		return result, err
+	}
+	sh.store(result)
+	return result, nil
+}
+
+func (s *shardedDriver) Version(orgID uuid.UUID) uint64 {
+	sh := s.shard(orgID)
+	if sh == nil {
+		return 0
+	}
+
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	return sh.driver.Version(orgID)
+}
+
+func (s *shardedDriver) GetVersionedFoldersByOrgID(orgID uuid.UUID) ([]Folder, uint64) {
+	sh := s.shard(orgID)
+	if sh == nil {
+		return []Folder{}, 0
+	}
+
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	return sh.driver.GetVersionedFoldersByOrgID(orgID)
+}
+
+func (s *shardedDriver) GetVersionedChildFolders(orgID uuid.UUID, name string) ([]Folder, uint64, error) {
+	if sh := s.shard(orgID); sh != nil {
+		sh.mu.RLock()
+		if sh.names[name] > 0 {
+			defer sh.mu.RUnlock()
+			return sh.driver.GetVersionedChildFolders(orgID, name)
+		}
+		sh.mu.RUnlock()
+	}
+
+	// Not found, though the version read is still that of the organisation
+	var version uint64 = s.Version(orgID)
+	if _, found := s.findName(name, orgID); found {
+		return []Folder{}, version, ErrFolderNotInOrg
+	}
+	return []Folder{}, version, ErrFolderNotFound
+}
+
+func (s *shardedDriver) MoveFolderIfVersion(version uint64, name string, dst string) ([]Folder, uint64, error) {
+	var newVersion uint64
+	result, err := s.moveFolder(name, dst, func(sh *shard) ([]Folder, error) {
+		var result []Folder
+		var err error
+		result, newVersion, err = sh.driver.MoveFolderIfVersion(version, name, dst)
+		return sh.mutate(result, err)
+	})
+	return result, newVersion, err
+}
+
+func (s *shardedDriver) CreateFolderIfVersion(orgID uuid.UUID, version uint64, name string, parent string) ([]Folder, uint64, error) {
+	sh := s.shardOrCreate(orgID)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.mutateVersioned(sh.driver.CreateFolderIfVersion(orgID, version, name, parent))
+}
+
+func (s *shardedDriver) MoveFolderByPathIfVersion(orgID uuid.UUID, version uint64, src string, dst string) ([]Folder, uint64, error) {
+	sh := s.shard(orgID)
+	if sh == nil {
+		return (&driver{}).MoveFolderByPathIfVersion(orgID, version, src, dst)
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.mutateVersioned(sh.driver.MoveFolderByPathIfVersion(orgID, version, src, dst))
+}
+
+func (s *shardedDriver) RenameFolderIfVersion(orgID uuid.UUID, version uint64, path string, newName string) ([]Folder, uint64, error) {
+	sh := s.shard(orgID)
+	if sh == nil {
+		return (&driver{}).RenameFolderIfVersion(orgID, version, path, newName)
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.mutateVersioned(sh.driver.RenameFolderIfVersion(orgID, version, path, newName))
+}
+
+func (s *shardedDriver) DeleteFolderIfVersion(orgID uuid.UUID, version uint64, path string) ([]Folder, uint64, error) {
+	sh := s.shard(orgID)
+	if sh == nil {
+		return (&driver{}).DeleteFolderIfVersion(orgID, version, path)
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.mutateVersioned(sh.driver.DeleteFolderIfVersion(orgID, version, path))
+}
+
+func (s *shardedDriver) Subscribe(orgID uuid.UUID, filter func(Event) bool) (<-chan Event, func()) {
+	return s.events.Subscribe(orgID, filter)
+}
+
+// mutateVersioned is mutate for the versioned mutations, so must be called holding the shard's write lock
+func (sh *shard) mutateVersioned(result []Folder, version uint64, err error) ([]Folder, uint64, error) {
+	result, err = sh.mutate(result, err)
+	return result, version, err
+}
@@ -0,0 +1,207 @@
+package folder_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_folder_ShardedDriver(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+	var newOrgId = uuid.FromStringOrNil("0b7bd0fb-4bbf-4a2f-a8a2-1c4fdb2a3c5e")
+
+	d := folder.NewShardedDriver([]folder.Folder{
+		{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+		{Name: "other", OrgId: otherOrgId, Paths: "other"},
+		{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+		{Name: "charlie", OrgId: validOrgId, Paths: "charlie"},
+	})
+
+	res, err := d.MoveFolder("bravo", "charlie")
+	require.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+		{Name: "bravo", OrgId: validOrgId, Paths: "charlie.bravo"},
+		{Name: "charlie", OrgId: validOrgId, Paths: "charlie"},
+	}, res, "Mutations return only the organisation's folders")
+
+	assert.Equal(t, []folder.Folder{
+		{Name: "other", OrgId: otherOrgId, Paths: "other"},
+	}, d.GetFoldersByOrgID(otherOrgId))
+
+	_, err = d.MoveFolder("bravo", "other")
+	assert.ErrorIs(t, err, folder.ErrMoveToOtherOrg)
+	_, err = d.MoveFolder("bravo", "missing")
+	assert.ErrorIs(t, err, folder.ErrDestinationNotFound)
+	_, err = d.MoveFolder("missing", "alpha")
+	assert.ErrorIs(t, err, folder.ErrSourceNotFound)
+	_, err = d.MoveFolder("charlie", "bravo")
+	assert.ErrorIs(t, err, folder.ErrMoveToChild)
+
+	_, err = d.GetAllChildFolders(validOrgId, "other")
+	assert.ErrorIs(t, err, folder.ErrFolderNotInOrg)
+	_, err = d.GetAllChildFolders(validOrgId, "missing")
+	assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+
+	_, err = d.RenameFolder(validOrgId, "charlie.bravo", "delta")
+	require.NoError(t, err)
+	_, err = d.MoveFolder("bravo", "alpha")
+	assert.ErrorIs(t, err, folder.ErrSourceNotFound, "The name index follows renames")
+
+	_, err = d.DeleteFolder(newOrgId, "missing")
+	assert.ErrorIs(t, err, folder.ErrFolderNotFound, "Organisations without folders have nothing to delete")
+	assert.Equal(t, []folder.Folder{}, d.GetFoldersByOrgID(newOrgId))
+
+	res, err = d.CreateFolder(newOrgId, "echo", "")
+	require.NoError(t, err)
+	assert.Equal(t, []folder.Folder{{Name: "echo", OrgId: newOrgId, Paths: "echo"}}, res)
+
+	children, err := d.GetAllChildFolders(validOrgId, "charlie")
+	require.NoError(t, err)
+	assert.Equal(t, []folder.Folder{{Name: "delta", OrgId: validOrgId, Paths: "charlie.delta"}}, children)
+}
+
+// Test_folder_ShardedDriver_Stress reorganises several organisations at once while others read them.
+// Run with -race to check for data races.
+func Test_folder_ShardedDriver_Stress(t *testing.T) {
+	t.Parallel()
+
+	const orgs, iterations = 8, 200
+
+	var folders []folder.Folder
+	var orgIds []uuid.UUID
+	for i := 0; i < orgs; i++ {
+		var orgId uuid.UUID = uuid.Must(uuid.NewV4())
+		orgIds = append(orgIds, orgId)
+		folders = append(folders,
+			folder.Folder{Name: "left", OrgId: orgId, Paths: "left"},
+			folder.Folder{Name: "right", OrgId: orgId, Paths: "right"},
+			folder.Folder{Name: "mover", OrgId: orgId, Paths: "left.mover"},
+		)
+	}
+	d := folder.NewShardedDriver(folders)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*orgs)
+
+	for _, orgId := range orgIds {
+		wg.Add(2)
+		go func(orgId uuid.UUID) {
+			defer wg.Done()
+			var from, to string = "left", "right"
+			for j := 0; j < iterations; j++ {
+				if _, err := d.MoveFolderByPath(orgId, from+".mover", to); err != nil {
+					errs <- fmt.Errorf("move mover from %s to %s: %w", from, to, err)
+					return
+				}
+				from, to = to, from
+			}
+		}(orgId)
+
+		go func(orgId uuid.UUID) {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				if problems := folder.Validate(d.GetFoldersByOrgID(orgId)); len(problems) > 0 {
+					errs <- problems[0]
+					return
+				}
+				if _, err := d.GetAllChildFolders(orgId, "mover"); err != nil {
+					errs <- fmt.Errorf("children of mover: %w", err)
+					return
+				}
+			}
+		}(orgId)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	for _, orgId := range orgIds {
+		children, err := d.GetAllChildFolders(orgId, "left")
+		require.NoError(t, err)
+		assert.Len(t, children, 1, "Each mover made an even number of moves, so is back beneath left")
+	}
+}
+
+// benchmarkDrivers moves folders back and forth in parallel, each goroutine within its own organisation,
+// so a driver which locks each organisation separately scales with GOMAXPROCS where a single lock does not.
+func benchmarkDrivers(b *testing.B, newDriver func([]folder.Folder) folder.IObservableDriver) {
+	const orgs, foldersPerOrg = 64, 256
+
+	var folders []folder.Folder
+	var orgIds []uuid.UUID
+	for i := 0; i < orgs; i++ {
+		var orgId uuid.UUID = uuid.Must(uuid.NewV4())
+		orgIds = append(orgIds, orgId)
+		folders = append(folders, folder.Folder{Name: "left", OrgId: orgId, Paths: "left"})
+		for j := 0; j < foldersPerOrg; j++ {
+			var name string = fmt.Sprintf("f%d", j)
+			folders = append(folders, folder.Folder{Name: name, OrgId: orgId, Paths: "left." + name})
+		}
+		folders = append(folders, folder.Folder{Name: "right", OrgId: orgId, Paths: "right"})
+	}
+	d := newDriver(folders)
+
+	var mu sync.Mutex
+	var next int
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		mu.Lock()
+		// Goroutines beyond the number of organisations share one, but each moves its own folder
+		var orgId uuid.UUID = orgIds[next%orgs]
+		var name string = fmt.Sprintf("f%d", next/orgs)
+		next++
+		mu.Unlock()
+
+		var from, to string = "left", "right"
+		for pb.Next() {
+			if _, err := d.MoveFolderByPath(orgId, from+"."+name, to); err != nil {
+				b.Error(err)
+				return
+			}
+			d.GetFoldersByOrgID(orgId)
+			from, to = to, from
+		}
+	})
+}
+
+func Benchmark_folder_ConcurrentDriver_ParallelOrgs(b *testing.B) {
+	benchmarkDrivers(b, folder.NewConcurrentDriver)
+}
+
+func Benchmark_folder_ShardedDriver_ParallelOrgs(b *testing.B) {
+	benchmarkDrivers(b, folder.NewShardedDriver)
+}
+
+func Test_folder_ShardedDriver_RepeatedNames(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+	d := folder.NewShardedDriver([]folder.Folder{
+		{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+		{Name: "golf", OrgId: otherOrgId, Paths: "golf"},
+		{Name: "golf", OrgId: validOrgId, Paths: "golf"},
+	})
+
+	// The destination is looked for within the source's organisation first, as IDriver leaves it to the driver
+	res, err := d.MoveFolder("alpha", "golf")
+	require.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "alpha", OrgId: validOrgId, Paths: "golf.alpha"},
+		{Name: "golf", OrgId: validOrgId, Paths: "golf"},
+	}, res)
+	assert.Equal(t, []folder.Folder{{Name: "golf", OrgId: otherOrgId, Paths: "golf"}}, d.GetFoldersByOrgID(otherOrgId))
+}
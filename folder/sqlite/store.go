@@ -0,0 +1,306 @@
+// Package sqlite provides a folder.Store persisted in an embedded SQLite database,
+// so folder trees survive restarts and can be larger than memory.
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Descendants of a path p are found with the range (p + ".", p + "/"),
+// as '/' is the character immediately after '.', which the (org_id, path) index serves.
+const schema = `
+CREATE TABLE IF NOT EXISTS folders (
+	id     INTEGER PRIMARY KEY,
+	org_id TEXT    NOT NULL,
+	name   TEXT    NOT NULL,
+	path   TEXT    NOT NULL,
+	depth  INTEGER NOT NULL,
+	UNIQUE (org_id, path)
+);
+CREATE INDEX IF NOT EXISTS folders_name ON folders (name);
+CREATE INDEX IF NOT EXISTS folders_org_depth ON folders (org_id, depth);
+`
+
+const selectColumns = `SELECT org_id, name, path, depth FROM folders`
+
+// A store which keeps folders in SQLite
+type store struct {
+	db     *sql.DB
+	limits folder.Limits
+}
+
+// querier is a database or a transaction to select from
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// row is a folder along with its stored depth
+type row struct {
+	folder folder.Folder
+	depth  int
+}
+
+// Open opens, creating if needed, the SQLite database at path.
+// Use ":memory:" for a store which lives only as long as the process.
+func Open(path string) (folder.Store, error) {
+	return OpenLimited(path, folder.Limits{})
+}
+
+// OpenLimited opens the SQLite database at path as Open does, with inserts and moves failing
+// rather than take an organisation beyond limits
+func OpenLimited(path string, limits folder.Limits) (folder.Store, error) {
+	if err := folder.CheckLimits(nil, limits); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+
+	// A single connection serialises writers, and keeps ":memory:" databases from being one per connection
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create schema: %w", err)
+	}
+
+	return &store{db: db, limits: limits}, nil
+}
+
+func (s *store) Close() error {
+	return s.db.Close()
+}
+
+func (s *store) InsertFolders(folders []folder.Folder) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO folders (org_id, name, path, depth) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	orgs := []uuid.UUID{}
+	for _, f := range folders {
+		if _, err := stmt.Exec(f.OrgId.String(), f.Name, f.Paths, pathDepth(f.Paths)); isUniqueViolation(err) {
+			return fmt.Errorf("%w: %q", folder.ErrFolderExists, f.Paths)
+		} else if err != nil {
+			return fmt.Errorf("insert folder %q: %w", f.Paths, err)
+		}
+		orgs = append(orgs, f.OrgId)
+	}
+
+	if err := s.checkLimits(tx, orgs...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetFoldersByOrgID returns an empty slice if the database cannot be read,
+// as IDriver has no way of reporting the failure.
+func (s *store) GetFoldersByOrgID(orgID uuid.UUID) []folder.Folder {
+	rows, err := s.query(selectColumns+` WHERE org_id = ? ORDER BY id`, orgID.String())
+	if err != nil {
+		return []folder.Folder{}
+	}
+	return foldersOf(rows)
+}
+
+func (s *store) GetAllChildFolders(orgID uuid.UUID, name string) ([]folder.Folder, error) {
+	sameNamedFolders, err := s.query(selectColumns+` WHERE name = ? ORDER BY id`, name)
+	if err != nil {
+		return []folder.Folder{}, err
+	}
+
+	if len(sameNamedFolders) == 0 {
+		return []folder.Folder{}, folder.ErrFolderNotFound
+	}
+
+	parentFolders := []row{}
+	for _, r := range sameNamedFolders {
+		if r.folder.OrgId == orgID {
+			parentFolders = append(parentFolders, r)
+		}
+	}
+
+	if len(parentFolders) == 0 {
+		return []folder.Folder{}, folder.ErrFolderNotInOrg
+	}
+
+	res := []folder.Folder{}
+	addedFilePaths := map[string]bool{}
+	for _, parent := range parentFolders {
+		children, err := s.query(
+			selectColumns+` WHERE org_id = ? AND path > ? AND path < ? ORDER BY id`,
+			orgID.String(), parent.folder.Paths+".", parent.folder.Paths+"/",
+		)
+		if err != nil {
+			return []folder.Folder{}, err
+		}
+
+		for _, c := range children {
+			if !addedFilePaths[c.folder.Paths] {
+				res = append(res, c.folder)
+				addedFilePaths[c.folder.Paths] = true
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func (s *store) MoveFolder(name string, dst string) ([]folder.Folder, error) {
+	if name == dst {
+		return []folder.Folder{}, folder.ErrMoveToSelf
+	}
+
+	srcFolders, err := s.query(selectColumns+` WHERE name = ? ORDER BY id LIMIT 1`, name)
+	if err != nil {
+		return []folder.Folder{}, err
+	}
+	dstFolders, err := s.query(selectColumns+` WHERE name = ? ORDER BY id LIMIT 1`, dst)
+	if err != nil {
+		return []folder.Folder{}, err
+	}
+
+	if len(srcFolders) == 0 {
+		return []folder.Folder{}, folder.ErrSourceNotFound
+	}
+
+	if len(dstFolders) == 0 {
+		return []folder.Folder{}, folder.ErrDestinationNotFound
+	}
+
+	// Names are assumed unique, as with the in-memory driver
+	var srcFolder row = srcFolders[0]
+	var dstFolder row = dstFolders[0]
+
+	if srcFolder.folder.OrgId != dstFolder.folder.OrgId {
+		return []folder.Folder{}, folder.ErrMoveToOtherOrg
+	}
+
+	if strings.HasPrefix(dstFolder.folder.Paths, srcFolder.folder.Paths+".") {
+		return []folder.Folder{}, folder.ErrMoveToChild
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return []folder.Folder{}, err
+	}
+	defer tx.Rollback()
+
+	// substr counts characters rather than bytes in TEXT, so the prefix is measured in runes
+	var srcPath string = srcFolder.folder.Paths
+	var newPath string = dstFolder.folder.Paths + "." + srcFolder.folder.Name
+	_, err = tx.Exec(
+		`UPDATE folders SET path = ? || substr(path, ?), depth = depth + ?
+		WHERE org_id = ? AND (path = ? OR (path > ? AND path < ?))`,
+		newPath, utf8.RuneCountInString(srcPath)+1, dstFolder.depth+1-srcFolder.depth,
+		srcFolder.folder.OrgId.String(), srcPath, srcPath+".", srcPath+"/",
+	)
+	if isUniqueViolation(err) {
+		return []folder.Folder{}, fmt.Errorf("%w: %q", folder.ErrFolderExists, newPath)
+	} else if err != nil {
+		return []folder.Folder{}, fmt.Errorf("move folder %q: %w", srcPath, err)
+	}
+
+	if err := s.checkLimits(tx, srcFolder.folder.OrgId); err != nil {
+		return []folder.Folder{}, err
+	}
+
+	// Only the organisation moved within is read back, as the whole table may not fit in memory
+	moved, err := queryRows(tx, selectColumns+` WHERE org_id = ? ORDER BY id`, srcFolder.folder.OrgId.String())
+	if err != nil {
+		return []folder.Folder{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return []folder.Folder{}, err
+	}
+	return foldersOf(moved), nil
+}
+
+// isUniqueViolation checks if err is SQLite refusing a second folder at the same path in an organisation
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
+
+// checkLimits checks the folders of each organisation, as changed within tx, keep within the store's limits
+func (s *store) checkLimits(tx *sql.Tx, orgs ...uuid.UUID) error {
+	if s.limits == (folder.Limits{}) {
+		return nil
+	}
+
+	checked := map[uuid.UUID]bool{}
+	for _, orgID := range orgs {
+		if checked[orgID] {
+			continue
+		}
+		checked[orgID] = true
+
+		rows, err := queryRows(tx, selectColumns+` WHERE org_id = ? ORDER BY id`, orgID.String())
+		if err != nil {
+			return err
+		}
+		if err := folder.CheckLimits(foldersOf(rows), s.limits); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// query runs a select over the folders table, returning the rows read
+func (s *store) query(query string, args ...any) ([]row, error) {
+	return queryRows(s.db, query, args...)
+}
+
+// queryRows runs a select over the folders table with q, returning the rows read
+func queryRows(q querier, query string, args ...any) ([]row, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []row{}
+	for rows.Next() {
+		var orgID string
+		var r row
+		if err := rows.Scan(&orgID, &r.folder.Name, &r.folder.Paths, &r.depth); err != nil {
+			return nil, err
+		}
+		r.folder.OrgId = uuid.FromStringOrNil(orgID)
+		result = append(result, r)
+	}
+
+	return result, rows.Err()
+}
+
+// foldersOf strips the stored depth from rows
+func foldersOf(rows []row) []folder.Folder {
+	folders := make([]folder.Folder, 0, len(rows))
+	for _, r := range rows {
+		folders = append(folders, r.folder)
+	}
+	return folders
+}
+
+// pathDepth counts the labels in an ltree path
+func pathDepth(path string) int {
+	return strings.Count(path, ".") + 1
+}
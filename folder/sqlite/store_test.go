@@ -0,0 +1,276 @@
+package sqlite_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/folder/drivertest"
+	"github.com/georgechieng-sc/interns-2022/folder/sqlite"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+func openStore(t *testing.T, path string, folders []folder.Folder) folder.Store {
+	t.Helper()
+
+	s, err := sqlite.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+
+	require.NoError(t, s.InsertFolders(folders))
+	return s
+}
+
+func Test_sqlite_GetFoldersByOrgID(t *testing.T) {
+	t.Parallel()
+
+	s := openStore(t, ":memory:", []folder.Folder{
+		{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+		{Name: "other", OrgId: otherOrgId, Paths: "other"},
+		{Name: "child-fold", OrgId: validOrgId, Paths: "fold.child-fold"},
+	})
+
+	assert.Equal(t, []folder.Folder{
+		{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+		{Name: "child-fold", OrgId: validOrgId, Paths: "fold.child-fold"},
+	}, s.GetFoldersByOrgID(validOrgId))
+	assert.Equal(t, []folder.Folder{}, s.GetFoldersByOrgID(uuid.Must(uuid.NewV4())))
+}
+
+func Test_sqlite_GetAllChildFolders(t *testing.T) {
+	t.Parallel()
+
+	s := openStore(t, ":memory:", []folder.Folder{
+		{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+		{Name: "c1", OrgId: validOrgId, Paths: "fold.c1"},
+		{Name: "c2", OrgId: validOrgId, Paths: "fold.c1.c2"},
+		{Name: "fold-sibling", OrgId: validOrgId, Paths: "fold-sibling"},
+		{Name: "c3", OrgId: validOrgId, Paths: "fold-sibling.c3"},
+		{Name: "theirs", OrgId: otherOrgId, Paths: "theirs"},
+	})
+
+	tests := [...]struct {
+		testName string
+		name     string
+		want     []folder.Folder
+		err      error
+	}{
+		{
+			testName: "Nested children returned, label prefixed siblings are not",
+			name:     "fold",
+			want: []folder.Folder{
+				{Name: "c1", OrgId: validOrgId, Paths: "fold.c1"},
+				{Name: "c2", OrgId: validOrgId, Paths: "fold.c1.c2"},
+			},
+		},
+		{
+			testName: "Leaf folder has no children",
+			name:     "c2",
+			want:     []folder.Folder{},
+		},
+		{
+			testName: "Error: Folder does not exist",
+			name:     "missing",
+			err:      folder.ErrFolderNotFound,
+		},
+		{
+			testName: "Error: Folder belongs to another organisation",
+			name:     "theirs",
+			err:      folder.ErrFolderNotInOrg,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			folders, err := s.GetAllChildFolders(validOrgId, tt.name)
+
+			if tt.err == nil {
+				assert.NoError(t, err, tt.testName)
+				assert.Equal(t, tt.want, folders, tt.testName)
+			} else {
+				assert.ErrorIs(t, err, tt.err, tt.testName)
+			}
+		})
+	}
+}
+
+func Test_sqlite_MoveFolder(t *testing.T) {
+	t.Parallel()
+
+	tests := [...]struct {
+		testName string
+		src      string
+		dst      string
+		want     []folder.Folder
+		err      error
+	}{
+		{
+			testName: "Move folder with nested children",
+			src:      "c",
+			dst:      "a",
+			want: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "c", OrgId: validOrgId, Paths: "a.c"},
+				{Name: "c1", OrgId: validOrgId, Paths: "a.c.c1"},
+				{Name: "c1-nest", OrgId: validOrgId, Paths: "a.c.c1.c1-nest"},
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+			},
+		},
+		{
+			testName: "Move child folder to its grandparent",
+			src:      "c1",
+			dst:      "fold",
+			want: []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "c", OrgId: validOrgId, Paths: "fold.c"},
+				{Name: "c1", OrgId: validOrgId, Paths: "fold.c1"},
+				{Name: "c1-nest", OrgId: validOrgId, Paths: "fold.c1.c1-nest"},
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+			},
+		},
+		{testName: "Error: Cannot move a folder to itself", src: "c", dst: "c", err: folder.ErrMoveToSelf},
+		{testName: "Error: Cannot move a folder to a child of itself", src: "c", dst: "c1-nest", err: folder.ErrMoveToChild},
+		{testName: "Error: Cannot move a folder to a different organization", src: "c", dst: "b", err: folder.ErrMoveToOtherOrg},
+		{testName: "Error: Source folder does not exist", src: "missing", dst: "a", err: folder.ErrSourceNotFound},
+		{testName: "Error: Destination folder does not exist", src: "c", dst: "missing", err: folder.ErrDestinationNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			s := openStore(t, ":memory:", []folder.Folder{
+				{Name: "fold", OrgId: validOrgId, Paths: "fold"},
+				{Name: "c", OrgId: validOrgId, Paths: "fold.c"},
+				{Name: "c1", OrgId: validOrgId, Paths: "fold.c.c1"},
+				{Name: "c1-nest", OrgId: validOrgId, Paths: "fold.c.c1.c1-nest"},
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+				{Name: "b", OrgId: otherOrgId, Paths: "b"},
+			})
+			folders, err := s.MoveFolder(tt.src, tt.dst)
+
+			if tt.err == nil {
+				assert.NoError(t, err, tt.testName)
+				assert.Equal(t, tt.want, folders, tt.testName)
+			} else {
+				assert.ErrorIs(t, err, tt.err, tt.testName)
+			}
+		})
+	}
+}
+
+func Test_sqlite_MoveFolder_NonASCII(t *testing.T) {
+	t.Parallel()
+
+	s := openStore(t, ":memory:", []folder.Folder{
+		{Name: "a", OrgId: validOrgId, Paths: "a"},
+		{Name: "é", OrgId: validOrgId, Paths: "é"},
+		{Name: "x", OrgId: validOrgId, Paths: "é.x"},
+		{Name: "日本", OrgId: validOrgId, Paths: "é.x.日本"},
+	})
+
+	want := []folder.Folder{
+		{Name: "a", OrgId: validOrgId, Paths: "a"},
+		{Name: "é", OrgId: validOrgId, Paths: "a.é"},
+		{Name: "x", OrgId: validOrgId, Paths: "a.é.x"},
+		{Name: "日本", OrgId: validOrgId, Paths: "a.é.x.日本"},
+	}
+	folders, err := s.MoveFolder("é", "a")
+	require.NoError(t, err)
+	assert.Equal(t, want, folders)
+	assert.Equal(t, want, s.GetFoldersByOrgID(validOrgId))
+}
+
+func Test_sqlite_MoveFolder_Exists(t *testing.T) {
+	t.Parallel()
+
+	folders := []folder.Folder{
+		{Name: "a", OrgId: validOrgId, Paths: "a"},
+		{Name: "b", OrgId: validOrgId, Paths: "a.b"},
+		{Name: "c", OrgId: validOrgId, Paths: "c"},
+		{Name: "b", OrgId: validOrgId, Paths: "c.b"},
+	}
+	s := openStore(t, ":memory:", folders)
+
+	// The first b is moved beneath c, which already has a b
+	_, err := s.MoveFolder("b", "c")
+	assert.ErrorIs(t, err, folder.ErrFolderExists)
+	assert.Equal(t, folders, s.GetFoldersByOrgID(validOrgId), "A failed move changes nothing")
+}
+
+func Test_sqlite_PersistsAcrossRestarts(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "folders.db")
+
+	s, err := sqlite.Open(path)
+	require.NoError(t, err)
+	require.NoError(t, s.InsertFolders([]folder.Folder{
+		{Name: "a", OrgId: validOrgId, Paths: "a"},
+		{Name: "b", OrgId: validOrgId, Paths: "b"},
+		{Name: "c", OrgId: validOrgId, Paths: "b.c"},
+	}))
+	_, err = s.MoveFolder("b", "a")
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	reopened := openStore(t, path, nil)
+	children, err := reopened.GetAllChildFolders(validOrgId, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, []folder.Folder{
+		{Name: "b", OrgId: validOrgId, Paths: "a.b"},
+		{Name: "c", OrgId: validOrgId, Paths: "a.b.c"},
+	}, children)
+}
+
+func Test_sqlite_InsertFolders_DuplicatePath(t *testing.T) {
+	t.Parallel()
+
+	s := openStore(t, ":memory:", []folder.Folder{{Name: "a", OrgId: validOrgId, Paths: "a"}})
+
+	err := s.InsertFolders([]folder.Folder{{Name: "a", OrgId: validOrgId, Paths: "a"}})
+	assert.ErrorIs(t, err, folder.ErrFolderExists)
+	assert.Len(t, s.GetFoldersByOrgID(validOrgId), 1)
+}
+
+func Test_sqlite_Limits(t *testing.T) {
+	t.Parallel()
+
+	_, err := sqlite.OpenLimited(":memory:", folder.Limits{MaxDepth: -1})
+	assert.ErrorIs(t, err, folder.ErrInvalidLimits)
+
+	s, err := sqlite.OpenLimited(":memory:", folder.Limits{MaxFolders: 3, MaxDepth: 2})
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+
+	folders := []folder.Folder{
+		{Name: "a", OrgId: validOrgId, Paths: "a"},
+		{Name: "b", OrgId: validOrgId, Paths: "a.b"},
+		{Name: "c", OrgId: validOrgId, Paths: "c"},
+	}
+	require.NoError(t, s.InsertFolders(folders))
+
+	err = s.InsertFolders([]folder.Folder{
+		{Name: "d", OrgId: otherOrgId, Paths: "d"},
+		{Name: "e", OrgId: validOrgId, Paths: "e"},
+	})
+	assert.ErrorIs(t, err, folder.ErrTooManyFolders, "An import beyond a limit is rejected")
+	assert.Equal(t, folders, s.GetFoldersByOrgID(validOrgId))
+	assert.Empty(t, s.GetFoldersByOrgID(otherOrgId), "Nothing of a rejected import is inserted")
+
+	_, err = s.MoveFolder("a", "c")
+	assert.ErrorIs(t, err, folder.ErrTooDeep)
+	assert.Equal(t, folders, s.GetFoldersByOrgID(validOrgId), "A move beyond a limit changes nothing")
+
+	_, err = s.MoveFolder("b", "c")
+	assert.NoError(t, err)
+}
+
+func Test_sqlite_Conformance(t *testing.T) {
+	t.Parallel()
+
+	drivertest.RunConformance(t, func(folders []folder.Folder) folder.IDriver {
+		return openStore(t, ":memory:", folders)
+	})
+}
@@ -17,15 +17,6 @@ import (
 // There's no real need for you to be editting these, but feel free to tweak it to suit your needs.
 // If you do make changes here, be ready to discuss why these changes were made.
 
-// how many trees you want to generate
-const MaxRootSet = 4
-
-// maximum possible children per node
-const MaxChild = 4
-
-// max depth of the tree
-const MaxDepth = 5
-
 // the default orgID that we will be using for testing
 const DefaultOrgID = "c1556e17-b7c0-45a3-a6ae-9546248fb17a"
 
@@ -35,61 +26,12 @@ type Folder struct {
 	Paths string    `json:"paths"`
 }
 
+// GenerateData makes random folders shaped by DefaultGeneratorConfig, from a fresh seed each call.
+// Use a Generator to make the same folders again.
 func GenerateData() []Folder {
-	rng, _ := codename.DefaultRNG()
-	tree := []Folder{}
-
-	for i := 0; i < MaxRootSet; i++ {
-		orgId := uuid.FromStringOrNil(DefaultOrgID)
-		if i%3 == 0 {
-			orgId = uuid.Must(uuid.NewV4())
-		}
-
-		name := codename.Generate(rng, 0)
-
-		subtree := make(chan []Folder)
-		go func() {
-			subtree <- generateTree(1, []Folder{
-				{
-					Name:  name,
-					OrgId: orgId,
-					Paths: name,
-				},
-			})
-		}()
-		tree = append(tree, <-subtree...)
-	}
-
-	return tree
-}
-
-func generateTree(depth int, tree []Folder) []Folder {
-	rng, _ := codename.DefaultRNG()
-
-	if depth >= MaxDepth {
-		return tree
-	}
-
-	for _, t := range tree {
-		numOfChild := rng.Int()%MaxChild + 1
-		for i := 0; i < numOfChild; i++ {
-			name := codename.Generate(rng, 0)
-
-			childTree := make(chan []Folder)
-			go func() {
-				childTree <- generateTree(depth+1, []Folder{
-					{
-						Name:  name,
-						OrgId: t.OrgId,
-						Paths: t.Paths + "." + name,
-					},
-				})
-			}()
-			tree = append(tree, <-childTree...)
-		}
-	}
-
-	return tree
+	seed, _ := codename.NewCryptoSeed()
+	g, _ := NewGenerator(seed, DefaultGeneratorConfig)
+	return g.Generate()
 }
 
 func MarshalJson(b interface{}) []byte {
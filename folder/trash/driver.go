@@ -0,0 +1,244 @@
+// Package trash deletes folders into a per-organisation trash, from which they can be restored.
+//
+// A deleted folder and its descendants are removed from the driver wrapped, so are hidden from its reads,
+// and kept as an Item along with their original paths and when they were deleted. Items are kept in memory,
+// until restored or purged once older than the retention period.
+//
+// Only the deletion reaches the driver wrapped, so over a store which persists, such as the wal or sqlite packages',
+// a restart loses the trash while the folders stay deleted. Restore anything needed before closing the store.
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+)
+
+// DefaultRetention is how long items are kept when unconfigured
+const DefaultRetention = 30 * 24 * time.Hour
+
+// ErrItemNotFound is returned for an item which is not in the organisation's trash
+var ErrItemNotFound = errors.New("item is not in the trash")
+
+// ErrParentGone is returned when restoring an item to its original path, whose parent no longer exists
+var ErrParentGone = errors.New("parent of the deleted folder no longer exists, so it must be restored elsewhere")
+
+// Config tunes how long a Driver keeps deleted folders
+type Config struct {
+	// Retention is how long an item is kept before Purge removes it. Zero uses DefaultRetention.
+	Retention time.Duration
+
+	// Clock returns the current time. Nil uses time.Now.
+	Clock func() time.Time
+}
+
+// Item is a deleted folder along with its descendants
+type Item struct {
+	ID        uuid.UUID `json:"id"`
+	OrgID     uuid.UUID `json:"org_id"`
+	Path      string    `json:"path"`
+	DeletedAt time.Time `json:"deleted_at"`
+
+	// Folders are the deleted folder and its descendants, at their original paths with parents before children.
+	Folders []folder.Folder `json:"folders"`
+}
+
+// Driver is a folder.IStatefulDriver whose DeleteFolder moves folders into the trash.
+// It is safe for concurrent use if the driver it wraps is, making one mutation at a time
+// so no folder can be added beneath one while it is being deleted.
+type Driver struct {
+	mu     sync.Mutex
+	driver folder.IStatefulDriver
+	cfg    Config
+	items  map[uuid.UUID][]Item // by organisation, oldest first
+}
+
+// NewDriver creates a driver keeping the folders deleted from d
+func NewDriver(d folder.IStatefulDriver, cfg Config) *Driver {
+	if cfg.Retention <= 0 {
+		cfg.Retention = DefaultRetention
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+	return &Driver{driver: d, cfg: cfg, items: map[uuid.UUID][]Item{}}
+}
+
+func (d *Driver) GetFoldersByOrgID(orgID uuid.UUID) []folder.Folder {
+	return d.driver.GetFoldersByOrgID(orgID)
+}
+
+func (d *Driver) GetAllChildFolders(orgID uuid.UUID, name string) ([]folder.Folder, error) {
+	return d.driver.GetAllChildFolders(orgID, name)
+}
+
+func (d *Driver) MoveFolder(name string, dst string) ([]folder.Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.driver.MoveFolder(name, dst)
+}
+
+func (d *Driver) CreateFolder(orgID uuid.UUID, name string, parent string) ([]folder.Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.driver.CreateFolder(orgID, name, parent)
+}
+
+func (d *Driver) MoveFolderByPath(orgID uuid.UUID, src string, dst string) ([]folder.Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.driver.MoveFolderByPath(orgID, src, dst)
+}
+
+func (d *Driver) RenameFolder(orgID uuid.UUID, path string, newName string) ([]folder.Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.driver.RenameFolder(orgID, path, newName)
+}
+
+// DeleteFolder removes the folder at path along with its descendants, keeping them in the trash
+func (d *Driver) DeleteFolder(orgID uuid.UUID, path string) ([]folder.Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	deleted := subtree(d.driver.GetFoldersByOrgID(orgID), path)
+	folders, err := d.driver.DeleteFolder(orgID, path)
+	if err != nil {
+		return folders, err
+	}
+
+	d.items[orgID] = append(d.items[orgID], Item{
+		ID:        uuid.Must(uuid.NewV4()),
+		OrgID:     orgID,
+		Path:      path,
+		DeletedAt: d.cfg.Clock(),
+		Folders:   deleted,
+	})
+	return folders, nil
+}
+
+// Trash returns the items in an organisation's trash, oldest first
+func (d *Driver) Trash(orgID uuid.UUID) []Item {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Item{}, d.items[orgID]...)
+}
+
+// Restore puts the folders of an item back where they were, removing it from the trash.
+// It fails with ErrParentGone if the folder's parent has since been deleted, moved or renamed,
+// when RestoreTo can put the folders elsewhere instead.
+func (d *Driver) Restore(orgID uuid.UUID, id uuid.UUID) ([]folder.Folder, error) {
+	return d.restore(orgID, id, func(item Item) (string, error) {
+		var parent string = folder.ParentPath(item.Path)
+		if parent != "" && !hasPath(d.driver.GetFoldersByOrgID(orgID), parent) {
+			return "", fmt.Errorf("%w: %q", ErrParentGone, parent)
+		}
+		return parent, nil
+	})
+}
+
+// RestoreTo puts the folders of an item beneath the folder at parent, or at the root if parent is empty,
+// removing it from the trash
+func (d *Driver) RestoreTo(orgID uuid.UUID, id uuid.UUID, parent string) ([]folder.Folder, error) {
+	return d.restore(orgID, id, func(Item) (string, error) {
+		return parent, nil
+	})
+}
+
+// restore recreates the folders of an item beneath the parent chosen for it, undoing any it created if one fails
+func (d *Driver) restore(orgID uuid.UUID, id uuid.UUID, chooseParent func(Item) (string, error)) ([]folder.Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var index int = -1
+	for i, item := range d.items[orgID] {
+		if item.ID == id {
+			index = i
+		}
+	}
+	if index < 0 {
+		return []folder.Folder{}, ErrItemNotFound
+	}
+
+	var item Item = d.items[orgID][index]
+	parent, err := chooseParent(item)
+	if err != nil {
+		return []folder.Folder{}, err
+	}
+
+	var root string = folder.JoinPath(parent, item.Folders[0].Name)
+	if hasPath(d.driver.GetFoldersByOrgID(orgID), root) {
+		return []folder.Folder{}, fmt.Errorf("%w: %q", folder.ErrFolderExists, root)
+	}
+
+	var folders []folder.Folder
+	for i, f := range item.Folders {
+		var path string = root + strings.TrimPrefix(f.Paths, item.Path)
+		folders, err = d.driver.CreateFolder(orgID, f.Name, folder.ParentPath(path))
+		if err != nil {
+			if i > 0 {
+				d.driver.DeleteFolder(orgID, root)
+			}
+			return []folder.Folder{}, err
+		}
+	}
+
+	d.items[orgID] = append(d.items[orgID][:index:index], d.items[orgID][index+1:]...)
+	return folders, nil
+}
+
+// Purge removes the items deleted longer ago than the retention period, returning how many it removed
+func (d *Driver) Purge() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var cutoff time.Time = d.cfg.Clock().Add(-d.cfg.Retention)
+	var purged int = 0
+	for orgID, items := range d.items {
+		kept := []Item{}
+		for _, item := range items {
+			if item.DeletedAt.Before(cutoff) {
+				purged++
+			} else {
+				kept = append(kept, item)
+			}
+		}
+
+		if len(kept) == 0 {
+			delete(d.items, orgID)
+		} else {
+			d.items[orgID] = kept
+		}
+	}
+	return purged
+}
+
+// subtree returns the folder at path and its descendants, parents before children
+func subtree(folders []folder.Folder, path string) []folder.Folder {
+	result := []folder.Folder{}
+	for _, f := range folders {
+		if f.Paths == path || folder.IsDescendantPath(path, f.Paths) {
+			result = append(result, f)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return strings.Count(result[i].Paths, ".") < strings.Count(result[j].Paths, ".")
+	})
+	return result
+}
+
+// hasPath checks if a folder is at path
+func hasPath(folders []folder.Folder, path string) bool {
+	for _, f := range folders {
+		if f.Paths == path {
+			return true
+		}
+	}
+	return false
+}
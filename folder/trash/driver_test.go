@@ -0,0 +1,233 @@
+package trash_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/folder/drivertest"
+	"github.com/georgechieng-sc/interns-2022/folder/trash"
+	"github.com/georgechieng-sc/interns-2022/folder/wal"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var deletedAt = time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func Test_trash_DeleteFolder(t *testing.T) {
+	t.Parallel()
+
+	sampleFolders := drivertest.ChildScenario()
+
+	// Children come ahead of their parents, which the trash must put back in order
+	folders := drivertest.ChildScenario()
+	slices.Reverse(folders)
+	d := trash.NewDriver(folder.NewDriver(folders), trash.Config{
+		Clock: func() time.Time { return deletedAt },
+	})
+
+	_, err := d.DeleteFolder(drivertest.OrgID, "alpha.bravo")
+	require.NoError(t, err)
+	_, err = d.DeleteFolder(drivertest.OrgID, "missing")
+	assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+
+	assert.ElementsMatch(t, []folder.Folder{sampleFolders[0], sampleFolders[3], sampleFolders[4]}, d.GetFoldersByOrgID(drivertest.OrgID), "Deleted folders are hidden")
+	_, err = d.GetAllChildFolders(drivertest.OrgID, "bravo")
+	assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+	children, err := d.GetAllChildFolders(drivertest.OrgID, "alpha")
+	require.NoError(t, err)
+	assert.Equal(t, []folder.Folder{sampleFolders[3]}, children)
+
+	items := d.Trash(drivertest.OrgID)
+	require.Len(t, items, 1)
+	assert.NotEqual(t, uuid.Nil, items[0].ID)
+	assert.Equal(t, drivertest.OrgID, items[0].OrgID)
+	assert.Equal(t, "alpha.bravo", items[0].Path)
+	assert.Equal(t, deletedAt, items[0].DeletedAt)
+	assert.Equal(t, []folder.Folder{sampleFolders[1], sampleFolders[2]}, items[0].Folders, "Parents come before children")
+	assert.Empty(t, d.Trash(drivertest.OtherOrgID), "Each org has its own trash")
+}
+
+func Test_trash_Restore(t *testing.T) {
+	t.Parallel()
+
+	sampleFolders := drivertest.ChildScenario()
+
+	tests := [...]struct {
+		testName string
+		prepare  func(d *trash.Driver)
+		parent   *string
+		want     []folder.Folder
+		err      error
+	}{
+		{
+			testName: "Restore to the original path",
+			want:     []folder.Folder{sampleFolders[0], sampleFolders[3], sampleFolders[4], sampleFolders[1], sampleFolders[2]},
+		},
+		{
+			testName: "Restore elsewhere",
+			parent:   ptr("echo"),
+			want: []folder.Folder{
+				sampleFolders[0], sampleFolders[3], sampleFolders[4],
+				{Name: "bravo", OrgId: drivertest.OrgID, Paths: "echo.bravo"},
+				{Name: "charlie", OrgId: drivertest.OrgID, Paths: "echo.bravo.charlie"},
+			},
+		},
+		{
+			testName: "Restore to the root",
+			parent:   ptr(""),
+			want: []folder.Folder{
+				sampleFolders[0], sampleFolders[3], sampleFolders[4],
+				{Name: "bravo", OrgId: drivertest.OrgID, Paths: "bravo"},
+				{Name: "charlie", OrgId: drivertest.OrgID, Paths: "bravo.charlie"},
+			},
+		},
+		{
+			testName: "Parent gone",
+			prepare: func(d *trash.Driver) {
+				d.RenameFolder(drivertest.OrgID, "alpha", "alpine")
+			},
+			err: trash.ErrParentGone,
+		},
+		{
+			testName: "Missing alternative parent",
+			parent:   ptr("zulu"),
+			err:      folder.ErrFolderNotFound,
+		},
+		{
+			testName: "Folder recreated at the path since",
+			prepare: func(d *trash.Driver) {
+				d.CreateFolder(drivertest.OrgID, "bravo", "alpha")
+			},
+			err: folder.ErrFolderExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			var now time.Time = deletedAt
+			d := trash.NewDriver(drivertest.NewChildDriver(), trash.Config{
+				Clock: func() time.Time { return now },
+			})
+			_, err := d.DeleteFolder(drivertest.OrgID, "alpha.bravo")
+			require.NoError(t, err)
+			if tt.prepare != nil {
+				tt.prepare(d)
+			}
+			before := d.GetFoldersByOrgID(drivertest.OrgID)
+
+			var id uuid.UUID = d.Trash(drivertest.OrgID)[0].ID
+			var folders []folder.Folder
+			if tt.parent == nil {
+				folders, err = d.Restore(drivertest.OrgID, id)
+			} else {
+				folders, err = d.RestoreTo(drivertest.OrgID, id, *tt.parent)
+			}
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Equal(t, before, d.GetFoldersByOrgID(drivertest.OrgID), "A failed restore changes nothing")
+				assert.Len(t, d.Trash(drivertest.OrgID), 1, "A failed restore keeps the item")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, d.GetFoldersByOrgID(drivertest.OrgID))
+			assert.Contains(t, folders, tt.want[len(tt.want)-1])
+			assert.Empty(t, d.Trash(drivertest.OrgID), "A restored item leaves the trash")
+
+			_, err = d.Restore(drivertest.OrgID, id)
+			assert.ErrorIs(t, err, trash.ErrItemNotFound)
+		})
+	}
+}
+
+func Test_trash_Restore_Undone(t *testing.T) {
+	t.Parallel()
+
+	// A limit reached part way through a restore undoes the folders already restored
+	limited, err := folder.NewLimitedDriver(folder.NewDriver, drivertest.ChildScenario(), folder.Limits{MaxFolders: 5})
+	require.NoError(t, err)
+	d := trash.NewDriver(limited, trash.Config{})
+
+	_, err = d.DeleteFolder(drivertest.OrgID, "alpha.bravo")
+	require.NoError(t, err)
+	_, err = d.CreateFolder(drivertest.OrgID, "golf", "")
+	require.NoError(t, err)
+	before := d.GetFoldersByOrgID(drivertest.OrgID)
+
+	_, err = d.Restore(drivertest.OrgID, d.Trash(drivertest.OrgID)[0].ID)
+	assert.ErrorIs(t, err, folder.ErrTooManyFolders)
+	assert.Equal(t, before, d.GetFoldersByOrgID(drivertest.OrgID))
+	assert.Len(t, d.Trash(drivertest.OrgID), 1)
+}
+
+func Test_trash_Purge(t *testing.T) {
+	t.Parallel()
+
+	var now time.Time = deletedAt
+	d := trash.NewDriver(drivertest.NewChildDriver(), trash.Config{
+		Retention: 24 * time.Hour,
+		Clock:     func() time.Time { return now },
+	})
+
+	_, err := d.DeleteFolder(drivertest.OrgID, "echo")
+	require.NoError(t, err)
+	now = now.Add(12 * time.Hour)
+	_, err = d.DeleteFolder(drivertest.OrgID, "alpha")
+	require.NoError(t, err)
+	_, err = d.DeleteFolder(drivertest.OtherOrgID, "foxtrot")
+	require.NoError(t, err)
+
+	now = deletedAt.Add(24 * time.Hour)
+	assert.Equal(t, 0, d.Purge(), "Items are kept for the whole retention period")
+
+	now = deletedAt.Add(30 * time.Hour)
+	assert.Equal(t, 1, d.Purge())
+	items := d.Trash(drivertest.OrgID)
+	require.Len(t, items, 1)
+	assert.Equal(t, "alpha", items[0].Path)
+
+	now = deletedAt.Add(40 * time.Hour)
+	assert.Equal(t, 2, d.Purge())
+	assert.Empty(t, d.Trash(drivertest.OrgID))
+	assert.Empty(t, d.Trash(drivertest.OtherOrgID))
+}
+
+func Test_trash_Conformance(t *testing.T) {
+	t.Parallel()
+
+	drivertest.RunConformance(t, func(folders []folder.Folder) folder.IDriver {
+		return trash.NewDriver(folder.NewDriver(folders), trash.Config{})
+	})
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func Test_trash_Restart(t *testing.T) {
+	t.Parallel()
+
+	sampleFolders := drivertest.ChildScenario()
+
+	dir := t.TempDir()
+	store, err := wal.Open(dir, drivertest.ChildScenario(), wal.Config{})
+	require.NoError(t, err)
+	d := trash.NewDriver(store, trash.Config{})
+	_, err = d.DeleteFolder(drivertest.OrgID, "alpha.bravo")
+	require.NoError(t, err)
+	require.Len(t, d.Trash(drivertest.OrgID), 1)
+	require.NoError(t, store.Close())
+
+	store, err = wal.Open(dir, nil, wal.Config{})
+	require.NoError(t, err)
+	defer store.Close()
+	d = trash.NewDriver(store, trash.Config{})
+
+	assert.Empty(t, d.Trash(drivertest.OrgID), "The trash is not kept by the wrapped driver")
+	assert.ElementsMatch(t, []folder.Folder{sampleFolders[0], sampleFolders[3], sampleFolders[4]}, d.GetFoldersByOrgID(drivertest.OrgID), "The deletion is kept")
+}
@@ -0,0 +1,37 @@
+package folder
+
+import (
+	"fmt"
+
+	"github.com/gofrs/uuid"
+)
+
+// Validate checks folders form well-formed trees, returning an error for each problem found:
+// a path which is not made of valid labels ending in the folder's name,
+// a path repeated within an organisation, or a folder whose parent is missing.
+func Validate(folders []Folder) []error {
+	problems := []error{}
+	paths := map[uuid.UUID]map[string]bool{}
+
+	for i, f := range folders {
+		if err := checkFolder(&f); err != nil {
+			problems = append(problems, fmt.Errorf("folder %d %q: %w", i, f.Paths, err))
+		}
+
+		if paths[f.OrgId] == nil {
+			paths[f.OrgId] = map[string]bool{}
+		}
+		if paths[f.OrgId][f.Paths] {
+			problems = append(problems, fmt.Errorf("folder %d %q: %w", i, f.Paths, ErrFolderExists))
+		}
+		paths[f.OrgId][f.Paths] = true
+	}
+
+	for i, f := range folders {
+		if parent := ParentPath(f.Paths); parent != "" && !paths[f.OrgId][parent] {
+			problems = append(problems, fmt.Errorf("folder %d %q: parent %w", i, f.Paths, ErrFolderNotFound))
+		}
+	}
+
+	return problems
+}
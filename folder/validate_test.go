@@ -0,0 +1,82 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_Validate(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+	tests := [...]struct {
+		testName string
+		folders  []folder.Folder
+		want     []error
+	}{
+		{
+			testName: "Valid trees in two organisations",
+			folders: []folder.Folder{
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+				{Name: "b", OrgId: validOrgId, Paths: "a.b"},
+				{Name: "a", OrgId: otherOrgId, Paths: "a"},
+			},
+			want: []error{},
+		},
+		{
+			testName: "Child listed before its parent",
+			folders: []folder.Folder{
+				{Name: "b", OrgId: validOrgId, Paths: "a.b"},
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+			},
+			want: []error{},
+		},
+		{
+			testName: "Name not matching last label",
+			folders: []folder.Folder{
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+				{Name: "c", OrgId: validOrgId, Paths: "a.b"},
+			},
+			want: []error{folder.ErrInvalidPath},
+		},
+		{
+			testName: "Duplicate path",
+			folders: []folder.Folder{
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+				{Name: "a", OrgId: validOrgId, Paths: "a"},
+			},
+			want: []error{folder.ErrFolderExists},
+		},
+		{
+			testName: "Parent in another organisation",
+			folders: []folder.Folder{
+				{Name: "a", OrgId: otherOrgId, Paths: "a"},
+				{Name: "b", OrgId: validOrgId, Paths: "a.b"},
+			},
+			want: []error{folder.ErrFolderNotFound},
+		},
+		{
+			testName: "Empty label and missing parent",
+			folders: []folder.Folder{
+				{Name: "b", OrgId: validOrgId, Paths: "a..b"},
+			},
+			want: []error{folder.ErrInvalidPath, folder.ErrFolderNotFound},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			problems := folder.Validate(tt.folders)
+
+			if assert.Len(t, problems, len(tt.want), tt.testName) {
+				for i, err := range tt.want {
+					assert.ErrorIs(t, problems[i], err, tt.testName)
+				}
+			}
+		})
+	}
+}
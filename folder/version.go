@@ -0,0 +1,72 @@
+package folder
+
+import "github.com/gofrs/uuid"
+
+func (f *driver) Version(orgID uuid.UUID) uint64 {
+	return f.versions[orgID]
+}
+
+// bump increases the version of an organisation after a mutation changes its folders
+func (f *driver) bump(orgID uuid.UUID) {
+	if f.versions == nil {
+		f.versions = map[uuid.UUID]uint64{}
+	}
+	f.versions[orgID]++
+}
+
+// ifVersion runs mutate only if orgID is at version, returning the version left behind
+func (f *driver) ifVersion(orgID uuid.UUID, version uint64, mutate func() ([]Folder, error)) ([]Folder, uint64, error) {
+	if f.Version(orgID) != version {
+		return []Folder{}, f.Version(orgID), ErrVersionConflict
+	}
+
+	result, err := mutate()
+	return result, f.Version(orgID), err
+}
+
+func (f *driver) GetVersionedFoldersByOrgID(orgID uuid.UUID) ([]Folder, uint64) {
+	return f.GetFoldersByOrgID(orgID), f.Version(orgID)
+}
+
+func (f *driver) GetVersionedChildFolders(orgID uuid.UUID, name string) ([]Folder, uint64, error) {
+	children, err := f.GetAllChildFolders(orgID, name)
+	return children, f.Version(orgID), err
+}
+
+func (f *driver) MoveFolderIfVersion(version uint64, name string, dst string) ([]Folder, uint64, error) {
+	var srcFolders []Folder = findFoldersByName(&f.folders, name)
+	if len(srcFolders) == 0 {
+		// Leave MoveFolder to report the missing folder
+		result, err := f.MoveFolder(name, dst)
+		return result, 0, err
+	}
+
+	// Refer to Assumption
+	return f.ifVersion(srcFolders[0].OrgId, version, func() ([]Folder, error) {
+		return f.MoveFolder(name, dst)
+	})
+}
+
+func (f *driver) CreateFolderIfVersion(orgID uuid.UUID, version uint64, name string, parent string) ([]Folder, uint64, error) {
+	return f.ifVersion(orgID, version, func() ([]Folder, error) {
+		return f.CreateFolder(orgID, name, parent)
+	})
+}
+
+func (f *driver) MoveFolderByPathIfVersion(orgID uuid.UUID, version uint64, src string, dst string) ([]Folder, uint64, error) {
+	return f.ifVersion(orgID, version, func() ([]Folder, error) {
+		return f.MoveFolderByPath(orgID, src, dst)
+	})
+}
+
+func (f *driver) RenameFolderIfVersion(orgID uuid.UUID, version uint64, path string, newName string) ([]Folder, uint64, error) {
+	return f.ifVersion(orgID, version, func() ([]Folder, error) {
+		return f.RenameFolder(orgID, path, newName)
+	})
+}
+
+func (f *driver) DeleteFolderIfVersion(orgID uuid.UUID, version uint64, path string) ([]Folder, uint64, error) {
+	return f.ifVersion(orgID, version, func() ([]Folder, error) {
+		return f.DeleteFolder(orgID, path)
+	})
+}
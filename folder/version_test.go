@@ -0,0 +1,90 @@
+package folder_test
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_folder_VersionedDriver(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+	drivers := [...]struct {
+		testName  string
+		newDriver func([]folder.Folder) folder.IObservableDriver
+	}{
+		{testName: "Driver", newDriver: folder.NewDriver},
+		{testName: "Concurrent driver", newDriver: folder.NewConcurrentDriver},
+		{testName: "Sharded driver", newDriver: folder.NewShardedDriver},
+	}
+
+	for _, tt := range drivers {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			d := tt.newDriver([]folder.Folder{
+				{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+				{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+				{Name: "charlie", OrgId: validOrgId, Paths: "charlie"},
+				{Name: "other", OrgId: otherOrgId, Paths: "other"},
+			})
+
+			// Two admins read the same folders
+			_, first := d.GetVersionedFoldersByOrgID(validOrgId)
+			_, second, err := d.GetVersionedChildFolders(validOrgId, "alpha")
+			require.NoError(t, err)
+			assert.Equal(t, uint64(0), first)
+			assert.Equal(t, first, second)
+
+			// The first moves a folder, so the second's change is refused
+			_, version, err := d.MoveFolderByPathIfVersion(validOrgId, first, "alpha.bravo", "charlie")
+			require.NoError(t, err)
+			assert.Equal(t, uint64(1), version)
+
+			_, version, err = d.RenameFolderIfVersion(validOrgId, second, "alpha.bravo", "delta")
+			assert.ErrorIs(t, err, folder.ErrVersionConflict)
+			assert.Equal(t, uint64(1), version, "Conflicts return the current version")
+			assert.Contains(t, d.GetFoldersByOrgID(validOrgId),
+				folder.Folder{Name: "bravo", OrgId: validOrgId, Paths: "charlie.bravo"})
+
+			// Having read again, the second's change goes ahead
+			_, version, err = d.RenameFolderIfVersion(validOrgId, d.Version(validOrgId), "charlie.bravo", "delta")
+			require.NoError(t, err)
+			assert.Equal(t, uint64(2), version)
+
+			_, version, err = d.MoveFolderIfVersion(1, "delta", "alpha")
+			assert.ErrorIs(t, err, folder.ErrVersionConflict)
+			assert.Equal(t, uint64(2), version)
+			_, version, err = d.MoveFolderIfVersion(2, "delta", "alpha")
+			require.NoError(t, err)
+			assert.Equal(t, uint64(3), version)
+
+			_, version, err = d.CreateFolderIfVersion(validOrgId, 3, "echo", "alpha")
+			require.NoError(t, err)
+			assert.Equal(t, uint64(4), version)
+			_, version, err = d.DeleteFolderIfVersion(validOrgId, 4, "alpha.echo")
+			require.NoError(t, err)
+			assert.Equal(t, uint64(5), version)
+
+			// Failures and moves which change nothing leave the version alone
+			_, err = d.DeleteFolder(validOrgId, "missing")
+			assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+			_, version, err = d.MoveFolderByPathIfVersion(validOrgId, 5, "alpha.delta", "alpha")
+			require.NoError(t, err)
+			assert.Equal(t, uint64(5), version)
+
+			// Unversioned mutations still change the version
+			_, err = d.RenameFolder(validOrgId, "charlie", "foxtrot")
+			require.NoError(t, err)
+			assert.Equal(t, uint64(6), d.Version(validOrgId))
+
+			assert.Equal(t, uint64(0), d.Version(otherOrgId), "Each organisation is versioned separately")
+		})
+	}
+}
@@ -0,0 +1,375 @@
+// Package wal makes a stateful folder driver durable, by appending every mutation
+// to a checksummed write-ahead log before it takes effect, and periodically snapshotting
+// the folders in the same JSON format as sample.json.
+package wal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+)
+
+// DefaultSnapshotEvery is the number of logged operations between snapshots when unconfigured
+const DefaultSnapshotEvery = 1000
+
+const logFileName = "wal.log"
+
+const snapshotPrefix = "snapshot-"
+
+const snapshotSuffix = ".json"
+
+// Config tunes how a Driver trades durability against throughput
+type Config struct {
+	// SnapshotEvery is the number of logged operations after which a snapshot is written,
+	// and the log truncated. Zero uses DefaultSnapshotEvery.
+	SnapshotEvery int
+
+	// NoSync skips syncing the log to disk after each operation,
+	// so a crash of the machine (rather than the process) may lose recent operations.
+	NoSync bool
+
+	// Limits bound each organisation's folders, as with folder.NewLimitedDriver.
+	// Operations which would exceed them fail without being logged.
+	Limits folder.Limits
+}
+
+// Driver is a folder.IStatefulDriver whose mutations survive crashes
+type Driver struct {
+	mu      sync.Mutex
+	dir     string
+	cfg     Config
+	folders []folder.Folder
+	log     *os.File
+	logSize int64
+	seq     uint64
+	pending int
+}
+
+// Open recovers the folders kept in dir, replaying any operations logged since the latest snapshot.
+// A torn final record, left by a crash mid-write, is discarded.
+// initial seeds the folders only when dir holds no snapshot yet.
+// It fails if the folders seeded or recovered exceed cfg.Limits.
+func Open(dir string, initial []folder.Folder, cfg Config) (*Driver, error) {
+	if cfg.SnapshotEvery <= 0 {
+		cfg.SnapshotEvery = DefaultSnapshotEvery
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	d := &Driver{dir: dir, cfg: cfg}
+
+	found, err := d.loadSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		if err := folder.CheckLimits(initial, cfg.Limits); err != nil {
+			return nil, err
+		}
+		d.folders = append([]folder.Folder{}, initial...)
+		if err := d.writeSnapshot(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := d.replayLog(); err != nil {
+		if d.log != nil {
+			d.log.Close()
+		}
+		return nil, err
+	}
+
+	if err := folder.CheckLimits(d.folders, cfg.Limits); err != nil {
+		d.log.Close()
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Close snapshots the folders, so the next Open has no log to replay, and closes the log.
+func (d *Driver) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	err := d.snapshot()
+	if closeErr := d.log.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Snapshot writes the current folders to a snapshot and truncates the log.
+func (d *Driver) Snapshot() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.snapshot()
+}
+
+func (d *Driver) GetFoldersByOrgID(orgID uuid.UUID) []folder.Folder {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return folder.NewDriver(d.folders).GetFoldersByOrgID(orgID)
+}
+
+func (d *Driver) GetAllChildFolders(orgID uuid.UUID, name string) ([]folder.Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return folder.NewDriver(d.folders).GetAllChildFolders(orgID, name)
+}
+
+func (d *Driver) MoveFolder(name string, dst string) ([]folder.Folder, error) {
+	return d.apply(op{Kind: opMove, Name: name, Target: dst})
+}
+
+func (d *Driver) MoveFolderByPath(orgID uuid.UUID, src string, dst string) ([]folder.Folder, error) {
+	return d.apply(op{Kind: opMoveByPath, OrgID: orgID, Path: src, Target: dst})
+}
+
+func (d *Driver) CreateFolder(orgID uuid.UUID, name string, parent string) ([]folder.Folder, error) {
+	return d.apply(op{Kind: opCreate, OrgID: orgID, Name: name, Path: parent})
+}
+
+func (d *Driver) RenameFolder(orgID uuid.UUID, path string, newName string) ([]folder.Folder, error) {
+	return d.apply(op{Kind: opRename, OrgID: orgID, Path: path, Name: newName})
+}
+
+func (d *Driver) DeleteFolder(orgID uuid.UUID, path string) ([]folder.Folder, error) {
+	return d.apply(op{Kind: opDelete, OrgID: orgID, Path: path})
+}
+
+// apply validates an operation against a copy of the folders, logs it, and only then keeps its result.
+// Operations which fail are not logged.
+func (d *Driver) apply(o op) ([]folder.Folder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// An operation too large to replay is refused before it is applied, so it is never acknowledged
+	o.Seq = d.seq + 1
+	record, err := encodeRecord(o)
+	if err != nil {
+		return []folder.Folder{}, err
+	}
+
+	scratch, err := folder.NewLimitedDriver(folder.NewDriver, d.folders, d.cfg.Limits)
+	if err != nil {
+		return []folder.Folder{}, err
+	}
+	result, err := o.applyTo(scratch)
+	if err != nil {
+		return result, err
+	}
+
+	if err := d.appendRecord(record); err != nil {
+		return []folder.Folder{}, err
+	}
+
+	d.seq = o.Seq
+	d.folders = result
+	d.pending++
+
+	// A failed snapshot loses nothing, as the operation is already logged, so it is retried on the next one
+	if d.pending >= d.cfg.SnapshotEvery {
+		d.snapshot()
+	}
+
+	return result, nil
+}
+
+// appendRecord writes an encoded record to the end of the log, syncing it unless configured not to
+func (d *Driver) appendRecord(buf []byte) error {
+	if _, err := d.log.Write(buf); err != nil {
+		// Drop any partial record, so later records are not appended after garbage
+		d.log.Truncate(d.logSize)
+		return fmt.Errorf("append to log: %w", err)
+	}
+
+	if !d.cfg.NoSync {
+		if err := d.log.Sync(); err != nil {
+			d.log.Truncate(d.logSize)
+			return fmt.Errorf("sync log: %w", err)
+		}
+	}
+
+	d.logSize += int64(len(buf))
+	return nil
+}
+
+// snapshot writes a snapshot then empties the log, whose records the snapshot now covers
+func (d *Driver) snapshot() error {
+	if err := d.writeSnapshot(); err != nil {
+		return err
+	}
+
+	if err := d.log.Truncate(0); err != nil {
+		return fmt.Errorf("truncate log: %w", err)
+	}
+	d.logSize = 0
+	d.pending = 0
+
+	return nil
+}
+
+// writeSnapshot atomically writes the folders as of the current sequence number,
+// then removes older snapshots
+func (d *Driver) writeSnapshot() error {
+	var path string = filepath.Join(d.dir, snapshotName(d.seq))
+	var tmpPath string = path + ".tmp"
+
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(folder.MarshalJson(d.folders)); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	if err := syncDir(d.dir); err != nil {
+		return err
+	}
+
+	snapshots, err := d.snapshotSeqs()
+	if err != nil {
+		return err
+	}
+	for _, seq := range snapshots {
+		if seq < d.seq {
+			os.Remove(filepath.Join(d.dir, snapshotName(seq)))
+		}
+	}
+
+	return nil
+}
+
+// loadSnapshot reads the latest snapshot, reporting whether there was one
+func (d *Driver) loadSnapshot() (bool, error) {
+	snapshots, err := d.snapshotSeqs()
+	if err != nil || len(snapshots) == 0 {
+		return false, err
+	}
+
+	var latest uint64 = snapshots[len(snapshots)-1]
+	b, err := os.ReadFile(filepath.Join(d.dir, snapshotName(latest)))
+	if err != nil {
+		return false, err
+	}
+
+	folders := []folder.Folder{}
+	if err := json.Unmarshal(b, &folders); err != nil {
+		return false, fmt.Errorf("read snapshot %d: %w", latest, err)
+	}
+
+	d.folders = folders
+	d.seq = latest
+	return true, nil
+}
+
+// replayLog applies the logged operations newer than the loaded snapshot,
+// truncating a torn final record, and leaves the log open for appending
+func (d *Driver) replayLog() error {
+	file, err := os.OpenFile(filepath.Join(d.dir, logFileName), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	d.log = file
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	ops, offset, err := readRecords(file, info.Size())
+	if err != nil {
+		return err
+	}
+
+	if offset < info.Size() {
+		if err := file.Truncate(offset); err != nil {
+			return fmt.Errorf("truncate torn record: %w", err)
+		}
+	}
+	d.logSize = offset
+
+	driver := folder.NewDriver(d.folders)
+	for _, o := range ops {
+		// Records up to the snapshot remain if a crash came between writing it and truncating the log
+		if o.Seq <= d.seq {
+			continue
+		}
+
+		result, err := o.applyTo(driver)
+		if err != nil {
+			return fmt.Errorf("replay record %d: %w", o.Seq, err)
+		}
+
+		d.folders = result
+		d.seq = o.Seq
+		d.pending++
+	}
+
+	return nil
+}
+
+// snapshotSeqs lists the sequence numbers of the snapshots in dir, in ascending order
+func (d *Driver) snapshotSeqs() ([]uint64, error) {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	seqs := []uint64{}
+	for _, e := range entries {
+		var name string = e.Name()
+		if !strings.HasPrefix(name, snapshotPrefix) || !strings.HasSuffix(name, snapshotSuffix) {
+			continue
+		}
+
+		seq, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, snapshotPrefix), snapshotSuffix), 10, 64)
+		if err == nil {
+			seqs = append(seqs, seq)
+		}
+	}
+
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	return seqs, nil
+}
+
+// snapshotName names the snapshot taken after the operation with sequence number seq
+func snapshotName(seq uint64) string {
+	return fmt.Sprintf("%s%020d%s", snapshotPrefix, seq, snapshotSuffix)
+}
+
+// syncDir makes a rename within dir durable
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return f.Sync()
+}
@@ -0,0 +1,289 @@
+package wal_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/folder/drivertest"
+	"github.com/georgechieng-sc/interns-2022/folder/wal"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+
+var initialFolders = []folder.Folder{
+	{Name: "a", OrgId: validOrgId, Paths: "a"},
+	{Name: "b", OrgId: validOrgId, Paths: "b"},
+	{Name: "c", OrgId: validOrgId, Paths: "b.c"},
+}
+
+// mutate applies a fixed sequence of operations, returning the resulting folders
+func mutate(t *testing.T, d folder.IStatefulDriver) []folder.Folder {
+	t.Helper()
+
+	_, err := d.MoveFolder("b", "a")
+	require.NoError(t, err)
+	_, err = d.CreateFolder(validOrgId, "d", "a.b.c")
+	require.NoError(t, err)
+	_, err = d.RenameFolder(validOrgId, "a.b", "bravo")
+	require.NoError(t, err)
+	_, err = d.MoveFolderByPath(validOrgId, "a.bravo.c", "")
+	require.NoError(t, err)
+	folders, err := d.DeleteFolder(validOrgId, "c.d")
+	require.NoError(t, err)
+
+	return folders
+}
+
+func Test_wal_ReplaysAfterCrash(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	d, err := wal.Open(dir, initialFolders, wal.Config{})
+	require.NoError(t, err)
+
+	want := mutate(t, d)
+
+	// Without Close, nothing is snapshotted and recovery relies on the log alone
+	recovered, err := wal.Open(dir, nil, wal.Config{})
+	require.NoError(t, err)
+	defer recovered.Close()
+
+	assert.Equal(t, want, recovered.GetFoldersByOrgID(validOrgId))
+}
+
+func Test_wal_FailedOperationsAreNotLogged(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	d, err := wal.Open(dir, initialFolders, wal.Config{})
+	require.NoError(t, err)
+
+	_, err = d.MoveFolder("a", "missing")
+	assert.ErrorIs(t, err, folder.ErrDestinationNotFound)
+
+	info, err := os.Stat(filepath.Join(dir, "wal.log"))
+	require.NoError(t, err)
+	assert.Zero(t, info.Size())
+
+	recovered, err := wal.Open(dir, nil, wal.Config{})
+	require.NoError(t, err)
+	defer recovered.Close()
+	assert.Equal(t, initialFolders, recovered.GetFoldersByOrgID(validOrgId))
+}
+
+func Test_wal_OversizedOperation(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	d, err := wal.Open(dir, initialFolders, wal.Config{})
+	require.NoError(t, err)
+
+	_, err = d.CreateFolder(validOrgId, strings.Repeat("x", 2<<20), "a")
+	assert.ErrorIs(t, err, wal.ErrRecordTooLarge)
+	assert.Equal(t, initialFolders, d.GetFoldersByOrgID(validOrgId), "A refused operation changes nothing")
+
+	info, err := os.Stat(filepath.Join(dir, "wal.log"))
+	require.NoError(t, err)
+	assert.Zero(t, info.Size(), "A refused operation is not logged")
+
+	want, err := d.CreateFolder(validOrgId, "d", "a")
+	require.NoError(t, err)
+
+	recovered, err := wal.Open(dir, nil, wal.Config{})
+	require.NoError(t, err)
+	defer recovered.Close()
+	assert.Equal(t, want, recovered.GetFoldersByOrgID(validOrgId), "Operations after a refused one are replayed")
+}
+
+func Test_wal_TornFinalRecord(t *testing.T) {
+	t.Parallel()
+
+	tests := [...]struct {
+		testName string
+		tail     []byte
+	}{
+		{testName: "Partial header", tail: []byte{0, 0}},
+		{testName: "Partial payload", tail: []byte{0, 0, 0, 40, 1, 2, 3, 4, '{', '"'}},
+		{testName: "Checksum mismatch", tail: []byte{0, 0, 0, 2, 1, 2, 3, 4, '{', '}'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			dir := t.TempDir()
+			d, err := wal.Open(dir, initialFolders, wal.Config{})
+			require.NoError(t, err)
+			want := mutate(t, d)
+
+			logPath := filepath.Join(dir, "wal.log")
+			before, err := os.Stat(logPath)
+			require.NoError(t, err)
+			appendFile(t, logPath, tt.tail)
+
+			recovered, err := wal.Open(dir, nil, wal.Config{})
+			require.NoError(t, err, tt.testName)
+			assert.Equal(t, want, recovered.GetFoldersByOrgID(validOrgId), tt.testName)
+
+			after, err := os.Stat(logPath)
+			require.NoError(t, err)
+			assert.Equal(t, before.Size(), after.Size(), "Torn record is truncated")
+
+			// Records appended after recovery must replay too
+			want, err = recovered.CreateFolder(validOrgId, "e", "")
+			require.NoError(t, err)
+			again, err := wal.Open(dir, nil, wal.Config{})
+			require.NoError(t, err)
+			defer again.Close()
+			assert.Equal(t, want, again.GetFoldersByOrgID(validOrgId), tt.testName)
+		})
+	}
+}
+
+func Test_wal_CorruptRecordBeforeEnd(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	d, err := wal.Open(dir, initialFolders, wal.Config{})
+	require.NoError(t, err)
+	mutate(t, d)
+
+	logPath := filepath.Join(dir, "wal.log")
+	b, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	b[10] ^= 0xff
+	require.NoError(t, os.WriteFile(logPath, b, 0o644))
+
+	_, err = wal.Open(dir, nil, wal.Config{})
+	assert.ErrorIs(t, err, wal.ErrCorruptLog)
+}
+
+func Test_wal_CorruptLengthBeforeEnd(t *testing.T) {
+	t.Parallel()
+
+	tests := [...]struct {
+		testName string
+		length   []byte
+	}{
+		{testName: "Beyond the largest record", length: []byte{0x7f, 0, 0, 0}},
+		{testName: "Past the end of the log", length: []byte{0, 1, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			dir := t.TempDir()
+			d, err := wal.Open(dir, initialFolders, wal.Config{})
+			require.NoError(t, err)
+			mutate(t, d)
+
+			logPath := filepath.Join(dir, "wal.log")
+			b, err := os.ReadFile(logPath)
+			require.NoError(t, err)
+			copy(b[0:4], tt.length)
+			require.NoError(t, os.WriteFile(logPath, b, 0o644))
+
+			_, err = wal.Open(dir, nil, wal.Config{})
+			assert.ErrorIs(t, err, wal.ErrCorruptLog, "Later records are not dropped as a torn tail")
+		})
+	}
+}
+
+func Test_wal_PeriodicSnapshot(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	d, err := wal.Open(dir, initialFolders, wal.Config{SnapshotEvery: 5})
+	require.NoError(t, err)
+
+	want := mutate(t, d)
+
+	info, err := os.Stat(filepath.Join(dir, "wal.log"))
+	require.NoError(t, err)
+	assert.Zero(t, info.Size(), "Log is truncated once snapshotted")
+
+	snapshots, err := filepath.Glob(filepath.Join(dir, "snapshot-*.json"))
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1, "Older snapshots are removed")
+
+	b, err := os.ReadFile(snapshots[0])
+	require.NoError(t, err)
+	snapshot := []folder.Folder{}
+	require.NoError(t, json.Unmarshal(b, &snapshot), "Snapshot is in the sample.json format")
+	assert.Equal(t, want, snapshot)
+
+	recovered, err := wal.Open(dir, nil, wal.Config{})
+	require.NoError(t, err)
+	defer recovered.Close()
+	assert.Equal(t, want, recovered.GetFoldersByOrgID(validOrgId))
+}
+
+func Test_wal_SkipsRecordsCoveredBySnapshot(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	d, err := wal.Open(dir, initialFolders, wal.Config{})
+	require.NoError(t, err)
+	want := mutate(t, d)
+
+	logPath := filepath.Join(dir, "wal.log")
+	records, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+
+	require.NoError(t, d.Snapshot())
+
+	// Restore the records, as if the crash came between writing the snapshot and truncating the log
+	require.NoError(t, os.WriteFile(logPath, records, 0o644))
+
+	recovered, err := wal.Open(dir, nil, wal.Config{})
+	require.NoError(t, err)
+	defer recovered.Close()
+	assert.Equal(t, want, recovered.GetFoldersByOrgID(validOrgId))
+}
+
+func appendFile(t *testing.T, path string, b []byte) {
+	t.Helper()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = f.Write(b)
+	require.NoError(t, err)
+}
+
+func Test_wal_Conformance(t *testing.T) {
+	t.Parallel()
+
+	drivertest.RunConformance(t, func(folders []folder.Folder) folder.IDriver {
+		d, err := wal.Open(t.TempDir(), folders, wal.Config{})
+		require.NoError(t, err)
+		t.Cleanup(func() { d.Close() })
+		return d
+	})
+}
+
+func Test_wal_Limits(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, err := wal.Open(dir, initialFolders, wal.Config{Limits: folder.Limits{MaxFolders: 2}})
+	assert.ErrorIs(t, err, folder.ErrTooManyFolders, "Seeding beyond a limit is rejected")
+
+	d, err := wal.Open(dir, initialFolders, wal.Config{Limits: folder.Limits{MaxDepth: 2}})
+	require.NoError(t, err)
+	_, err = d.CreateFolder(validOrgId, "d", "b.c")
+	assert.ErrorIs(t, err, folder.ErrTooDeep)
+	_, err = d.MoveFolder("b", "a")
+	assert.ErrorIs(t, err, folder.ErrTooDeep)
+
+	info, err := os.Stat(filepath.Join(dir, "wal.log"))
+	require.NoError(t, err)
+	assert.Zero(t, info.Size(), "Operations beyond a limit are not logged")
+	require.NoError(t, d.Close())
+
+	_, err = wal.Open(dir, nil, wal.Config{Limits: folder.Limits{MaxPathLength: 2}})
+	assert.ErrorIs(t, err, folder.ErrPathTooLong, "Recovering folders beyond a limit is rejected")
+}
@@ -0,0 +1,151 @@
+package wal
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+)
+
+// Each record is framed as a 4 byte payload length and a 4 byte CRC-32C of the payload,
+// both big endian, followed by the JSON encoded operation.
+const headerSize = 8
+
+// maxRecordSize bounds the payload length read from a header, so a corrupt length is not allocated.
+// Operations whose payload would exceed it are refused rather than logged.
+const maxRecordSize = 1 << 20
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// ErrCorruptLog is returned when a record before the end of the log fails its checksum
+// or its length runs over later records, which a torn write cannot explain.
+var ErrCorruptLog = errors.New("write-ahead log is corrupt")
+
+// ErrRecordTooLarge is returned for an operation too large to be logged, and so to be replayed
+var ErrRecordTooLarge = errors.New("operation is too large for the write-ahead log")
+
+// Operation kinds recorded in the log
+const (
+	opMove       = "move"
+	opMoveByPath = "move_by_path"
+	opCreate     = "create"
+	opRename     = "rename"
+	opDelete     = "delete"
+)
+
+// op is a logged mutation, holding the arguments of the IStatefulDriver method it replays
+type op struct {
+	Seq    uint64    `json:"seq"`
+	Kind   string    `json:"kind"`
+	OrgID  uuid.UUID `json:"org_id"`
+	Name   string    `json:"name,omitempty"`
+	Path   string    `json:"path,omitempty"`
+	Target string    `json:"target,omitempty"`
+}
+
+// applyTo runs the operation against a driver
+func (o op) applyTo(d folder.IStatefulDriver) ([]folder.Folder, error) {
+	switch o.Kind {
+	case opMove:
+		return d.MoveFolder(o.Name, o.Target)
+	case opMoveByPath:
+		return d.MoveFolderByPath(o.OrgID, o.Path, o.Target)
+	case opCreate:
+		return d.CreateFolder(o.OrgID, o.Name, o.Path)
+	case opRename:
+		return d.RenameFolder(o.OrgID, o.Path, o.Name)
+	case opDelete:
+		return d.DeleteFolder(o.OrgID, o.Path)
+	}
+	return []folder.Folder{}, fmt.Errorf("unknown operation %q", o.Kind)
+}
+
+// encodeRecord frames an operation for appending to the log, failing if readRecords would not read it back
+func encodeRecord(o op) ([]byte, error) {
+	payload, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) > maxRecordSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrRecordTooLarge, len(payload))
+	}
+
+	buf := make([]byte, headerSize+len(payload))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.Checksum(payload, castagnoli))
+	copy(buf[headerSize:], payload)
+	return buf, nil
+}
+
+// readRecords decodes every intact record in a log of the given size.
+// It returns the offset just past the last intact record, which is short of size
+// when the final record was torn by a crash mid-write.
+func readRecords(r io.Reader, size int64) ([]op, int64, error) {
+	ops := []op{}
+	var offset int64
+	header := make([]byte, headerSize)
+
+	for offset < size {
+		if size-offset < headerSize {
+			return ops, offset, nil
+		}
+		if _, err := io.ReadFull(r, header); err != nil {
+			return ops, offset, err
+		}
+
+		length := int64(binary.BigEndian.Uint32(header[0:4]))
+		end := offset + headerSize + length
+		if length > maxRecordSize || end > size {
+			// The checksum does not cover the length, so only a tail holding no intact record was torn
+			rest, err := io.ReadAll(r)
+			if err != nil {
+				return ops, offset, err
+			}
+			if containsRecord(rest) {
+				return ops, offset, fmt.Errorf("%w: bad length at offset %d", ErrCorruptLog, offset)
+			}
+			return ops, offset, nil
+		}
+
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return ops, offset, err
+		}
+
+		var o op
+		if crc32.Checksum(payload, castagnoli) != binary.BigEndian.Uint32(header[4:8]) ||
+			json.Unmarshal(payload, &o) != nil {
+			if end == size {
+				return ops, offset, nil
+			}
+			return ops, offset, fmt.Errorf("%w: bad record at offset %d", ErrCorruptLog, offset)
+		}
+
+		ops = append(ops, o)
+		offset = end
+	}
+
+	return ops, offset, nil
+}
+
+// containsRecord checks if an intact record starts anywhere in b
+func containsRecord(b []byte) bool {
+	for i := 0; i+headerSize <= len(b); i++ {
+		length := int(binary.BigEndian.Uint32(b[i : i+4]))
+		if length == 0 || length > len(b)-i-headerSize {
+			continue
+		}
+
+		payload := b[i+headerSize : i+headerSize+length]
+		var o op
+		if crc32.Checksum(payload, castagnoli) == binary.BigEndian.Uint32(b[i+4:i+8]) && json.Unmarshal(payload, &o) == nil {
+			return true
+		}
+	}
+	return false
+}
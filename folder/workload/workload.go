@@ -0,0 +1,346 @@
+// Package workload generates large synthetic folder sets for capacity planning, shaped like real ones:
+// a few huge organisations and a long tail of small ones, deep narrow trees and wide shallow ones,
+// and names repeated across branches.
+//
+// Folders are written as they are made, each tree in depth first order, so only the path being made
+// and a bounded pool of names are held in memory however many folders are generated.
+package workload
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/lucasepe/codename"
+)
+
+// Format is the file format a workload is written in
+type Format string
+
+const (
+	// JSON is an array of folders, laid out as sample.json is
+	JSON Format = "json"
+
+	// NDJSON is a folder object per line
+	NDJSON Format = "ndjson"
+)
+
+// ErrInvalidConfig is returned by Generate for a config it cannot follow
+var ErrInvalidConfig = errors.New("workload config needs an organisation, no negative folders, a tree size of at least one and rates within [0, 1] with deep and wide rates summing to at most 1")
+
+// namePool is how many of an organisation's recent names are kept to repeat
+const namePool = 1024
+
+// Config describes a workload
+type Config struct {
+	Seed int64
+
+	// Folders is how many folders are made in all.
+	Folders int
+
+	// Orgs is how many organisations the folders are shared between. The first is folder.DefaultOrgID.
+	Orgs int
+
+	// Skew is the exponent of the power law sizing the organisations: the organisation ranked n
+	// gets folders in proportion to 1/n^Skew, so 0 shares them evenly and larger values favour the first.
+	Skew float64
+
+	// MaxTree is the most folders a tree has. An organisation's folders are split into trees of
+	// random sizes up to it.
+	MaxTree int
+
+	// DeepRate and WideRate are the shares of trees which are deep and narrow, or wide and shallow.
+	// The rest branch a few ways at each level.
+	DeepRate float64
+	WideRate float64
+
+	// DuplicateRate is the chance a folder takes a name used recently elsewhere in its organisation.
+	DuplicateRate float64
+}
+
+// DefaultConfig is a million folders, most belonging to a few of the thousand organisations
+var DefaultConfig = Config{
+	Folders:       1_000_000,
+	Orgs:          1000,
+	Skew:          1.2,
+	MaxTree:       10_000,
+	DeepRate:      0.1,
+	WideRate:      0.2,
+	DuplicateRate: 0.05,
+}
+
+// Summary describes a generated workload
+type Summary struct {
+	Folders  int `json:"folders"`
+	Orgs     int `json:"orgs"`
+	Trees    int `json:"trees"`
+	MaxDepth int `json:"max_depth"`
+
+	// LargestOrg is how many folders the largest organisation has.
+	LargestOrg int `json:"largest_org"`
+}
+
+// The shape of a tree: how many children each folder has, and how deep the tree goes
+type shape struct {
+	minChildren int
+	maxChildren int
+	maxDepth    int
+}
+
+var (
+	deep  = shape{minChildren: 1, maxChildren: 2, maxDepth: 64}
+	wide  = shape{minChildren: 50, maxChildren: 1000, maxDepth: 3}
+	bushy = shape{minChildren: 2, maxChildren: 8, maxDepth: 12}
+)
+
+// Generate writes the folders of a workload to w in the given format, returning a summary of them.
+// The same config always writes the same folders.
+func Generate(w io.Writer, format Format, cfg Config) (Summary, error) {
+	if cfg.Orgs < 1 || cfg.Folders < 0 || cfg.MaxTree < 1 || cfg.Skew < 0 ||
+		!inRate(cfg.DeepRate) || !inRate(cfg.WideRate) || !inRate(cfg.DuplicateRate) || cfg.DeepRate+cfg.WideRate > 1 {
+		return Summary{}, ErrInvalidConfig
+	}
+
+	out := NewWriter(w, format)
+	g := &generation{cfg: cfg, rng: rand.New(rand.NewSource(cfg.Seed)), out: out}
+
+	sizes := orgSizes(cfg)
+	for i, size := range sizes {
+		orgID := uuid.FromStringOrNil(folder.DefaultOrgID)
+		if i > 0 {
+			orgID = g.newOrgID()
+		}
+		if size == 0 {
+			continue
+		}
+
+		if err := g.org(orgID, size); err != nil {
+			return Summary{}, err
+		}
+		g.summary.Orgs++
+		g.summary.LargestOrg = max(g.summary.LargestOrg, size)
+	}
+
+	if err := out.Close(); err != nil {
+		return Summary{}, err
+	}
+	return g.summary, nil
+}
+
+func inRate(rate float64) bool {
+	return rate >= 0 && rate <= 1
+}
+
+// orgSizes shares the folders between the organisations by the power law, largest first.
+// Rounding leftovers go to the largest organisations.
+func orgSizes(cfg Config) []int {
+	weights := make([]float64, cfg.Orgs)
+	var total float64 = 0
+	for i := range weights {
+		weights[i] = 1 / math.Pow(float64(i+1), cfg.Skew)
+		total += weights[i]
+	}
+
+	sizes := make([]int, cfg.Orgs)
+	var left int = cfg.Folders
+	for i, weight := range weights {
+		sizes[i] = int(float64(cfg.Folders) * weight / total)
+		left -= sizes[i]
+	}
+	for i := 0; left > 0; i, left = (i+1)%len(sizes), left-1 {
+		sizes[i]++
+	}
+	return sizes
+}
+
+// generation is the state of one run of Generate
+type generation struct {
+	cfg     Config
+	rng     *rand.Rand
+	out     *Writer
+	summary Summary
+
+	// The organisation being made, and the names recently used within it
+	orgID uuid.UUID
+	names []string
+	next  int
+}
+
+// newOrgID makes a random version 4 UUID
+func (g *generation) newOrgID() uuid.UUID {
+	var id uuid.UUID
+	g.rng.Read(id[:])
+	id.SetVersion(uuid.V4)
+	id.SetVariant(uuid.VariantRFC4122)
+	return id
+}
+
+// org writes an organisation's folders, as trees of random sizes
+func (g *generation) org(orgID uuid.UUID, size int) error {
+	g.orgID, g.names, g.next = orgID, g.names[:0], 0
+
+	roots := map[string]bool{}
+	for size > 0 {
+		var treeSize int = 1 + g.rng.Intn(min(size, g.cfg.MaxTree))
+		size -= treeSize
+
+		var s shape = bushy
+		switch r := g.rng.Float64(); {
+		case r < g.cfg.DeepRate:
+			s = deep
+		case r < g.cfg.DeepRate+g.cfg.WideRate:
+			s = wide
+		}
+
+		if err := g.tree(s, "", g.name(roots), treeSize, 1); err != nil {
+			return err
+		}
+		g.summary.Trees++
+	}
+	return nil
+}
+
+// tree writes a folder called name beneath parent, followed by size-1 descendants
+func (g *generation) tree(s shape, parent string, name string, size int, depth int) error {
+	var path string = name
+	if parent != "" {
+		path = parent + "." + name
+	}
+
+	if err := g.out.Write(folder.Folder{Name: name, OrgId: g.orgID, Paths: path}); err != nil {
+		return err
+	}
+	g.summary.Folders++
+	g.summary.MaxDepth = max(g.summary.MaxDepth, depth)
+
+	var left int = size - 1
+	if left == 0 {
+		return nil
+	}
+
+	// The last level above the shape's depth takes every folder left as a child
+	var children int = left
+	if depth+1 < s.maxDepth {
+		children = min(left, s.minChildren+g.rng.Intn(s.maxChildren-s.minChildren+1))
+	}
+
+	siblings := map[string]bool{}
+	for _, childSize := range g.split(left, children) {
+		if err := g.tree(s, path, g.name(siblings), childSize, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// split shares n folders between children subtrees of at least one folder each, unevenly
+func (g *generation) split(n int, children int) []int {
+	weights := make([]float64, children)
+	var total float64 = 0
+	for i := range weights {
+		weights[i] = g.rng.ExpFloat64()
+		total += weights[i]
+	}
+
+	sizes := make([]int, children)
+	var left int = n - children
+	for i, weight := range weights {
+		sizes[i] = 1 + int(float64(n-children)*weight/total)
+		left -= sizes[i] - 1
+	}
+	sizes[0] += left
+	return sizes
+}
+
+// name picks a name none of a folder's siblings have, repeating a recent name of the organisation
+// at the config's DuplicateRate
+func (g *generation) name(siblings map[string]bool) string {
+	if len(g.names) > 0 && g.rng.Float64() < g.cfg.DuplicateRate {
+		if name := g.names[g.rng.Intn(len(g.names))]; !siblings[name] {
+			siblings[name] = true
+			return name
+		}
+	}
+
+	for tries := 0; ; tries++ {
+		var tokenLength int = 0
+		if tries >= 3 {
+			tokenLength = tries
+		}
+
+		name := codename.Generate(g.rng, tokenLength)
+		if siblings[name] {
+			continue
+		}
+
+		siblings[name] = true
+		if len(g.names) < namePool {
+			g.names = append(g.names, name)
+		} else {
+			g.names[g.next] = name
+			g.next = (g.next + 1) % namePool
+		}
+		return name
+	}
+}
+
+// Writer writes folders one at a time in a file format, buffering its output until Close
+type Writer struct {
+	w      *bufio.Writer
+	format Format
+	count  int
+}
+
+// NewWriter returns a Writer of folders to w in the given format
+func NewWriter(w io.Writer, format Format) *Writer {
+	return &Writer{w: bufio.NewWriter(w), format: format}
+}
+
+// Write writes a folder
+func (w *Writer) Write(f folder.Folder) error {
+	if w.format == NDJSON {
+		b, err := json.Marshal(f)
+		if err != nil {
+			return err
+		}
+		w.count++
+		_, err = w.w.Write(append(b, '\n'))
+		return err
+	}
+
+	// Laid out as folder.MarshalJson lays out a slice
+	b, err := json.MarshalIndent(f, "\t", "\t")
+	if err != nil {
+		return err
+	}
+
+	var sep string = ",\n\t"
+	if w.count == 0 {
+		sep = "[\n\t"
+	}
+	w.count++
+	if _, err := w.w.WriteString(sep); err != nil {
+		return err
+	}
+	_, err = w.w.Write(b)
+	return err
+}
+
+// Close finishes the file and flushes it, leaving the underlying writer open
+func (w *Writer) Close() error {
+	if w.format != NDJSON {
+		var end string = "\n]"
+		if w.count == 0 {
+			end = "[]"
+		}
+		if _, err := w.w.WriteString(end); err != nil {
+			return err
+		}
+	}
+	return w.w.Flush()
+}
@@ -0,0 +1,149 @@
+package workload_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/folder/workload"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smallConfig is a workload quick enough to check folder by folder
+var smallConfig = workload.Config{
+	Seed:          42,
+	Folders:       20_000,
+	Orgs:          50,
+	Skew:          1.2,
+	MaxTree:       2000,
+	DeepRate:      0.2,
+	WideRate:      0.3,
+	DuplicateRate: 0.1,
+}
+
+func Test_workload_Generate(t *testing.T) {
+	t.Parallel()
+
+	var ndjson, again, array bytes.Buffer
+	summary, err := workload.Generate(&ndjson, workload.NDJSON, smallConfig)
+	require.NoError(t, err)
+	_, err = workload.Generate(&again, workload.NDJSON, smallConfig)
+	require.NoError(t, err)
+	assert.Equal(t, ndjson.Bytes(), again.Bytes(), "The same config writes the same folders")
+
+	_, err = workload.Generate(&array, workload.JSON, smallConfig)
+	require.NoError(t, err)
+
+	folders, err := folder.ReadNDJSON(&ndjson)
+	require.NoError(t, err)
+	assert.Empty(t, folder.Validate(folders))
+	assert.Equal(t, string(folder.MarshalJson(folders)), array.String(), "JSON is laid out as sample.json is")
+
+	orgs := map[uuid.UUID]int{}
+	names := map[uuid.UUID]map[string]bool{}
+	trees, depth, duplicates := 0, 0, 0
+	for _, f := range folders {
+		orgs[f.OrgId]++
+		if names[f.OrgId] == nil {
+			names[f.OrgId] = map[string]bool{}
+		}
+		if names[f.OrgId][f.Name] {
+			duplicates++
+		}
+		names[f.OrgId][f.Name] = true
+
+		if !strings.Contains(f.Paths, ".") {
+			trees++
+		}
+		depth = max(depth, strings.Count(f.Paths, ".")+1)
+	}
+
+	largest := 0
+	for _, n := range orgs {
+		largest = max(largest, n)
+	}
+
+	assert.Equal(t, workload.Summary{
+		Folders:    smallConfig.Folders,
+		Orgs:       len(orgs),
+		Trees:      trees,
+		MaxDepth:   depth,
+		LargestOrg: largest,
+	}, summary)
+	assert.Equal(t, largest, orgs[uuid.FromStringOrNil(folder.DefaultOrgID)], "The first organisation is the largest")
+	assert.Less(t, orgs[folders[len(folders)-1].OrgId]*20, largest, "Organisation sizes are skewed")
+	assert.Greater(t, depth, 20, "Some trees are deep")
+	assert.Greater(t, duplicates, smallConfig.Folders/20, "Names repeat across branches")
+}
+
+func Test_workload_Generate_Even(t *testing.T) {
+	t.Parallel()
+
+	cfg := workload.Config{Seed: 1, Folders: 1003, Orgs: 10, MaxTree: 50}
+	var buf bytes.Buffer
+	_, err := workload.Generate(&buf, workload.NDJSON, cfg)
+	require.NoError(t, err)
+
+	folders, err := folder.ReadNDJSON(&buf)
+	require.NoError(t, err)
+
+	orgs := map[uuid.UUID]int{}
+	for _, f := range folders {
+		orgs[f.OrgId]++
+	}
+	require.Len(t, orgs, 10)
+	for _, n := range orgs {
+		assert.InDelta(t, 100, n, 1, "Without skew organisations share the folders evenly")
+	}
+}
+
+func Test_workload_Generate_Empty(t *testing.T) {
+	t.Parallel()
+
+	tests := [...]struct {
+		format workload.Format
+		want   string
+	}{
+		{format: workload.JSON, want: "[]"},
+		{format: workload.NDJSON, want: ""},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		summary, err := workload.Generate(&buf, tt.format, workload.Config{Orgs: 1, MaxTree: 1})
+		require.NoError(t, err)
+		assert.Equal(t, workload.Summary{}, summary)
+		assert.Equal(t, tt.want, buf.String(), tt.format)
+	}
+}
+
+func Test_workload_Generate_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, cfg := range []workload.Config{
+		{Folders: 10, Orgs: 0, MaxTree: 10},
+		{Folders: -1, Orgs: 1, MaxTree: 10},
+		{Folders: 10, Orgs: 1, MaxTree: 0},
+		{Folders: 10, Orgs: 1, MaxTree: 10, Skew: -1},
+		{Folders: 10, Orgs: 1, MaxTree: 10, DeepRate: 0.6, WideRate: 0.6},
+		{Folders: 10, Orgs: 1, MaxTree: 10, DuplicateRate: 2},
+	} {
+		_, err := workload.Generate(io.Discard, workload.NDJSON, cfg)
+		assert.ErrorIs(t, err, workload.ErrInvalidConfig, "%+v", cfg)
+	}
+}
+
+func Benchmark_workload_Generate(b *testing.B) {
+	cfg := smallConfig
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cfg.Seed = int64(i)
+		if _, err := workload.Generate(io.Discard, workload.NDJSON, cfg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
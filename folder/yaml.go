@@ -0,0 +1,156 @@
+package folder
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/gofrs/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlOrg is an organisation's folders as a nested tree
+type yamlOrg struct {
+	OrgID   string      `yaml:"org_id"`
+	Folders []*yamlNode `yaml:"folders"`
+	line    int
+}
+
+// yamlNode is a folder along with its children, whose paths are implied by nesting
+type yamlNode struct {
+	Name     string      `yaml:"name"`
+	Children []*yamlNode `yaml:"children,omitempty"`
+	line     int
+}
+
+func (o *yamlOrg) UnmarshalYAML(value *yaml.Node) error {
+	type plain yamlOrg
+	o.line = value.Line
+	if err := checkYAMLKeys(value, "org_id", "folders"); err != nil {
+		return err
+	}
+	return value.Decode((*plain)(o))
+}
+
+func (n *yamlNode) UnmarshalYAML(value *yaml.Node) error {
+	type plain yamlNode
+	n.line = value.Line
+	if err := checkYAMLKeys(value, "name", "children"); err != nil {
+		return err
+	}
+	return value.Decode((*plain)(n))
+}
+
+// checkYAMLKeys rejects a mapping with a key other than those given, so a misspelt key is not silently dropped.
+// Decoder.KnownFields does this for plain structs, but is not passed on to Node.Decode within UnmarshalYAML.
+func checkYAMLKeys(value *yaml.Node, keys ...string) error {
+	if value.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i < len(value.Content); i += 2 {
+		var key *yaml.Node = value.Content[i]
+		if !slices.Contains(keys, key.Value) {
+			return &ParseError{Line: key.Line, Err: fmt.Errorf("unknown field %q", key.Value)}
+		}
+	}
+	return nil
+}
+
+// ReadYAML reads folders from a list of organisations, each holding a nested tree of folders.
+// Folders are returned with parents ahead of their children.
+func ReadYAML(r io.Reader) ([]Folder, error) {
+	orgs := []*yamlOrg{}
+	if err := yaml.NewDecoder(r).Decode(&orgs); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	// An organisation may be listed more than once, so paths are tracked across its entries
+	folders := []Folder{}
+	paths := map[uuid.UUID]map[string]bool{}
+	for _, org := range orgs {
+		orgId, err := uuid.FromString(org.OrgID)
+		if err != nil {
+			return nil, &ParseError{Line: org.line, Err: fmt.Errorf("invalid org_id: %w", err)}
+		}
+
+		if paths[orgId] == nil {
+			paths[orgId] = map[string]bool{}
+		}
+		if err := appendYAMLNodes(&folders, paths[orgId], org.Folders, orgId, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return folders, nil
+}
+
+// appendYAMLNodes flattens sibling nodes and their descendants into folders beneath parent,
+// adding their paths to those already read in the organisation
+func appendYAMLNodes(folders *[]Folder, paths map[string]bool, nodes []*yamlNode, orgId uuid.UUID, parent string) error {
+	for _, n := range nodes {
+		if n == nil {
+			continue
+		}
+
+		if !isValidName(n.Name) {
+			return &ParseError{Line: n.line, Err: fmt.Errorf("%w: %q", ErrInvalidName, n.Name)}
+		}
+
+		var f Folder = Folder{Name: n.Name, OrgId: orgId, Paths: JoinPath(parent, n.Name)}
+		if paths[f.Paths] {
+			return &ParseError{Line: n.line, Err: fmt.Errorf("%w: %q", ErrFolderExists, f.Paths)}
+		}
+		paths[f.Paths] = true
+		*folders = append(*folders, f)
+
+		if err := appendYAMLNodes(folders, paths, n.Children, orgId, f.Paths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteYAML writes folders as a list of organisations, each holding a nested tree of folders.
+// Every folder's parent must be among folders, and no path may appear twice in an organisation,
+// otherwise a ParseError gives the position of the offending folder, counting from 1.
+func WriteYAML(w io.Writer, folders []Folder) error {
+	orgs := []*yamlOrg{}
+	orgsById := map[uuid.UUID]*yamlOrg{}
+	nodes := map[uuid.UUID]map[string]*yamlNode{}
+
+	for i, f := range folders {
+		if _, ok := orgsById[f.OrgId]; !ok {
+			orgsById[f.OrgId] = &yamlOrg{OrgID: f.OrgId.String()}
+			orgs = append(orgs, orgsById[f.OrgId])
+			nodes[f.OrgId] = map[string]*yamlNode{}
+		}
+		if _, ok := nodes[f.OrgId][f.Paths]; ok {
+			return &ParseError{Line: i + 1, Err: fmt.Errorf("%w: %q", ErrFolderExists, f.Paths)}
+		}
+		nodes[f.OrgId][f.Paths] = &yamlNode{Name: f.Name}
+	}
+
+	// Attached in a second pass, so parents may come after their children
+	for i, f := range folders {
+		var node *yamlNode = nodes[f.OrgId][f.Paths]
+		var parent string = ParentPath(f.Paths)
+		if parent == "" {
+			orgsById[f.OrgId].Folders = append(orgsById[f.OrgId].Folders, node)
+			continue
+		}
+
+		parentNode, ok := nodes[f.OrgId][parent]
+		if !ok {
+			return &ParseError{Line: i + 1, Err: fmt.Errorf("%w: parent of %q", ErrFolderNotFound, f.Paths)}
+		}
+		parentNode.Children = append(parentNode.Children, node)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(orgs); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
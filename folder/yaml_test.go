@@ -0,0 +1,138 @@
+package folder_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_folder_WriteYAML(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+	folders := []folder.Folder{
+		{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+		{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+		{Name: "charlie", OrgId: validOrgId, Paths: "alpha.bravo.charlie"},
+		{Name: "delta", OrgId: validOrgId, Paths: "alpha.delta"},
+		{Name: "echo", OrgId: validOrgId, Paths: "echo"},
+		{Name: "foxtrot", OrgId: otherOrgId, Paths: "foxtrot"},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, folder.WriteYAML(&buf, folders))
+	assert.Equal(t, `- org_id: c59cc5c1-9b81-4d00-95e3-22c6efdaf134
+  folders:
+    - name: alpha
+      children:
+        - name: bravo
+          children:
+            - name: charlie
+        - name: delta
+    - name: echo
+- org_id: 5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3
+  folders:
+    - name: foxtrot
+`, buf.String())
+
+	read, err := folder.ReadYAML(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, folders, read, "Round trips")
+}
+
+func Test_folder_WriteYAML_MissingParent(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+
+	err := folder.WriteYAML(&bytes.Buffer{}, []folder.Folder{
+		{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+	})
+	assert.ErrorIs(t, err, folder.ErrFolderNotFound)
+}
+
+func Test_folder_WriteYAML_DuplicatePath(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	var otherOrgId = uuid.FromStringOrNil("5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3")
+
+	var buf bytes.Buffer
+	err := folder.WriteYAML(&buf, []folder.Folder{
+		{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+		{Name: "alpha", OrgId: otherOrgId, Paths: "alpha"},
+		{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+		{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+	})
+	assert.ErrorIs(t, err, folder.ErrFolderExists)
+
+	var parseErr *folder.ParseError
+	if assert.True(t, errors.As(err, &parseErr)) {
+		assert.Equal(t, 4, parseErr.Line)
+	}
+	assert.Empty(t, buf.String(), "Nothing is written")
+}
+
+func Test_folder_ReadYAML_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := [...]struct {
+		testName string
+		input    string
+		errLine  int
+	}{
+		{
+			testName: "Invalid org_id",
+			input:    "- org_id: c59cc5c1-9b81-4d00-95e3-22c6efdaf134\n  folders: []\n- org_id: nope\n  folders: []\n",
+			errLine:  3,
+		},
+		{
+			testName: "Name with a dot",
+			input:    "- org_id: c59cc5c1-9b81-4d00-95e3-22c6efdaf134\n  folders:\n    - name: alpha\n      children:\n        - name: bra.vo\n",
+			errLine:  5,
+		},
+		{
+			testName: "Duplicate sibling",
+			input:    "- org_id: c59cc5c1-9b81-4d00-95e3-22c6efdaf134\n  folders:\n    - name: alpha\n    - name: alpha\n",
+			errLine:  4,
+		},
+		{
+			testName: "Same path in two entries for an organisation",
+			input:    "- org_id: c59cc5c1-9b81-4d00-95e3-22c6efdaf134\n  folders:\n    - name: alpha\n- org_id: c59cc5c1-9b81-4d00-95e3-22c6efdaf134\n  folders:\n    - name: alpha\n",
+			errLine:  6,
+		},
+		{
+			testName: "Misspelt children",
+			input:    "- org_id: c59cc5c1-9b81-4d00-95e3-22c6efdaf134\n  folders:\n    - name: alpha\n      childrens:\n        - name: bravo\n",
+			errLine:  4,
+		},
+		{
+			testName: "Unknown organisation field",
+			input:    "- org_id: c59cc5c1-9b81-4d00-95e3-22c6efdaf134\n  folder:\n    - name: alpha\n",
+			errLine:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			_, err := folder.ReadYAML(strings.NewReader(tt.input))
+
+			var parseErr *folder.ParseError
+			if assert.True(t, errors.As(err, &parseErr), tt.testName) {
+				assert.Equal(t, tt.errLine, parseErr.Line, tt.testName)
+			}
+		})
+	}
+}
+
+func Test_folder_ReadYAML_Malformed(t *testing.T) {
+	t.Parallel()
+
+	_, err := folder.ReadYAML(strings.NewReader("- org_id: [\n"))
+	assert.ErrorContains(t, err, "line")
+}
@@ -0,0 +1,135 @@
+// Package benchcmp compares two runs of go test -bench, so regressions between them stand out.
+package benchcmp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Result is a benchmark's measurements, averaged over the times it was run
+type Result struct {
+	Name        string
+	Runs        int
+	NsPerOp     float64
+	BytesPerOp  float64
+	AllocsPerOp float64
+}
+
+// Parse reads the output of go test -bench, averaging the runs of each benchmark as given by -count.
+// Results are in the order each benchmark first appears, and other lines are skipped.
+func Parse(r io.Reader) ([]Result, error) {
+	results := []Result{}
+	index := map[string]int{}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+
+		var run Result = Result{Name: fields[0], Runs: 1}
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %q: %w", line, fields[i], err)
+			}
+
+			switch fields[i+1] {
+			case "ns/op":
+				run.NsPerOp = value
+			case "B/op":
+				run.BytesPerOp = value
+			case "allocs/op":
+				run.AllocsPerOp = value
+			}
+		}
+
+		i, seen := index[run.Name]
+		if !seen {
+			index[run.Name] = len(results)
+			results = append(results, run)
+			continue
+		}
+
+		// Keep a running mean
+		r := &results[i]
+		r.Runs++
+		r.NsPerOp += (run.NsPerOp - r.NsPerOp) / float64(r.Runs)
+		r.BytesPerOp += (run.BytesPerOp - r.BytesPerOp) / float64(r.Runs)
+		r.AllocsPerOp += (run.AllocsPerOp - r.AllocsPerOp) / float64(r.Runs)
+	}
+	return results, scanner.Err()
+}
+
+// Comparison is a benchmark measured in both runs
+type Comparison struct {
+	Old Result
+	New Result
+}
+
+// Compare pairs up the benchmarks found in both runs, in the order of the new run
+func Compare(old []Result, new []Result) []Comparison {
+	byName := map[string]Result{}
+	for _, r := range old {
+		byName[r.Name] = r
+	}
+
+	comparisons := []Comparison{}
+	for _, r := range new {
+		if o, found := byName[r.Name]; found {
+			comparisons = append(comparisons, Comparison{Old: o, New: r})
+		}
+	}
+	return comparisons
+}
+
+// change is the relative change from old to new, as a percentage
+func change(old float64, new float64) float64 {
+	if old == 0 {
+		if new == 0 {
+			return 0
+		}
+		return 100
+	}
+	return (new - old) / old * 100
+}
+
+// TimeChange is the percentage by which the time per op changed
+func (c Comparison) TimeChange() float64 {
+	return change(c.Old.NsPerOp, c.New.NsPerOp)
+}
+
+// AllocsChange is the percentage by which the allocations per op changed
+func (c Comparison) AllocsChange() float64 {
+	return change(c.Old.AllocsPerOp, c.New.AllocsPerOp)
+}
+
+// Regressed reports if the time or allocations per op grew by more than threshold percent
+func (c Comparison) Regressed(threshold float64) bool {
+	return c.TimeChange() > threshold || c.AllocsChange() > threshold
+}
+
+// Write writes the comparisons as a table, marking those which regressed by more than threshold percent
+func Write(w io.Writer, comparisons []Comparison, threshold float64) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tOLD NS/OP\tNEW NS/OP\tDELTA\tOLD ALLOCS/OP\tNEW ALLOCS/OP\tDELTA\t")
+	for _, c := range comparisons {
+		var mark string = ""
+		if c.Regressed(threshold) {
+			mark = "REGRESSED"
+		}
+
+		fmt.Fprintf(tw, "%s\t%.0f\t%.0f\t%+.1f%%\t%.0f\t%.0f\t%+.1f%%\t%s\n",
+			c.New.Name, c.Old.NsPerOp, c.New.NsPerOp, c.TimeChange(),
+			c.Old.AllocsPerOp, c.New.AllocsPerOp, c.AllocsChange(), mark)
+	}
+	return tw.Flush()
+}
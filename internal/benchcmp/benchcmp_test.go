@@ -0,0 +1,71 @@
+package benchcmp_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/internal/benchcmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const oldRun = `goos: linux
+goarch: amd64
+pkg: github.com/georgechieng-sc/interns-2022/folder
+Benchmark_folder_MoveFolder/bushy/1000   	   13000	     90000 ns/op	  120000 B/op	      20 allocs/op
+Benchmark_folder_MoveFolder/bushy/1000   	   13000	    110000 ns/op	  124000 B/op	      20 allocs/op
+Benchmark_folder_GetFoldersByOrgID/wide/1000   	  100000	     11000 ns/op	   64848 B/op	      10 allocs/op
+Benchmark_folder_Removed   	  100000	     11000 ns/op
+PASS
+ok  	github.com/georgechieng-sc/interns-2022/folder	10.996s
+`
+
+const newRun = `Benchmark_folder_GetFoldersByOrgID/wide/1000   	  100000	     10000 ns/op	   64848 B/op	      10 allocs/op
+Benchmark_folder_MoveFolder/bushy/1000   	   13000	    150000 ns/op	  122000 B/op	      21 allocs/op
+Benchmark_folder_Added   	  100000	     11000 ns/op
+`
+
+func Test_benchcmp_Parse(t *testing.T) {
+	t.Parallel()
+
+	results, err := benchcmp.Parse(strings.NewReader(oldRun))
+	require.NoError(t, err)
+	assert.Equal(t, []benchcmp.Result{
+		{Name: "Benchmark_folder_MoveFolder/bushy/1000", Runs: 2, NsPerOp: 100000, BytesPerOp: 122000, AllocsPerOp: 20},
+		{Name: "Benchmark_folder_GetFoldersByOrgID/wide/1000", Runs: 1, NsPerOp: 11000, BytesPerOp: 64848, AllocsPerOp: 10},
+		{Name: "Benchmark_folder_Removed", Runs: 1, NsPerOp: 11000},
+	}, results)
+
+	_, err = benchcmp.Parse(strings.NewReader("Benchmark_folder_X 10 fast ns/op\n"))
+	assert.Error(t, err)
+}
+
+func Test_benchcmp_Compare(t *testing.T) {
+	t.Parallel()
+
+	old, err := benchcmp.Parse(strings.NewReader(oldRun))
+	require.NoError(t, err)
+	new, err := benchcmp.Parse(strings.NewReader(newRun))
+	require.NoError(t, err)
+
+	comparisons := benchcmp.Compare(old, new)
+	require.Len(t, comparisons, 2, "Only benchmarks in both runs are compared")
+
+	assert.Equal(t, "Benchmark_folder_GetFoldersByOrgID/wide/1000", comparisons[0].New.Name)
+	assert.InDelta(t, -9.09, comparisons[0].TimeChange(), 0.01)
+	assert.False(t, comparisons[0].Regressed(10))
+
+	assert.Equal(t, 50.0, comparisons[1].TimeChange())
+	assert.Equal(t, 5.0, comparisons[1].AllocsChange())
+	assert.True(t, comparisons[1].Regressed(10))
+	assert.False(t, comparisons[1].Regressed(60))
+
+	var buf bytes.Buffer
+	require.NoError(t, benchcmp.Write(&buf, comparisons, 10))
+	assert.Equal(t, ""+
+		"NAME                                          OLD NS/OP  NEW NS/OP  DELTA   OLD ALLOCS/OP  NEW ALLOCS/OP  DELTA  \n"+
+		"Benchmark_folder_GetFoldersByOrgID/wide/1000  11000      10000      -9.1%   10             10             +0.0%  \n"+
+		"Benchmark_folder_MoveFolder/bushy/1000        100000     150000     +50.0%  20             21             +5.0%  REGRESSED\n",
+		buf.String())
+}
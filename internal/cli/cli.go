@@ -0,0 +1,334 @@
+// Package cli implements the folders command, which inspects and edits folder files
+// through the folder drivers.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/folder/workload"
+	"github.com/gofrs/uuid"
+)
+
+// Exit codes, so scripts can tell failures apart
+const (
+	ExitOK           = 0
+	ExitError        = 1
+	ExitUsage        = 2
+	ExitNotFound     = 3
+	ExitConflict     = 4
+	ExitInvalidMove  = 5
+	ExitInvalidInput = 6
+)
+
+// DefaultFile is the folder file read when none is given
+const DefaultFile = "folder/sample.json"
+
+// errUsage is returned for incorrect invocations, after the usage has been printed
+var errUsage = errors.New("usage")
+
+// errInvalidData is returned by validate when problems were found
+var errInvalidData = errors.New("folders are invalid")
+
+// errRegressed is returned by benchcmp when benchmarks got slower
+var errRegressed = errors.New("benchmarks regressed")
+
+// env is the state shared by a command's run
+type env struct {
+	file      string
+	org       uuid.UUID
+	format    string
+	write     bool
+	byPath    bool
+	addr      string
+	seed      int64
+	shape     folder.GeneratorConfig
+	load      workload.Config
+	threshold float64
+	limits    folder.Limits
+	folders   []folder.Folder
+	driver    folder.IObservableDriver // over folders, enforcing limits
+	stdin     io.Reader
+	stdout    io.Writer
+	stderr    io.Writer
+}
+
+// command is a subcommand of folders
+type command struct {
+	args    string
+	summary string
+	nargs   [2]int
+	org     bool
+	noFile  bool // runs without reading --file
+	flags   func(fs *flag.FlagSet, e *env)
+	run     func(e *env, args []string) error
+}
+
+var commands = map[string]*command{
+	"ls": {
+		summary: "list folders, of one organisation if --org is given",
+		run:     runLs,
+	},
+	"tree": {
+		summary: "show folders as trees, of one organisation if --org is given",
+		run:     runTree,
+	},
+	"children": {
+		args:    "<name>",
+		summary: "list all descendants of the folders called name",
+		nargs:   [2]int{1, 1},
+		org:     true,
+		run:     runChildren,
+	},
+	"move": {
+		args:    "<name> <dst>",
+		summary: "move the folder called name beneath the folder called dst, or by path with --org",
+		nargs:   [2]int{2, 2},
+		flags: func(fs *flag.FlagSet, e *env) {
+			fs.BoolVar(&e.byPath, "path", false, "address folders by path within --org, where an empty dst is the root")
+		},
+		run: runMove,
+	},
+	"create": {
+		args:    "<name> [parent-path]",
+		summary: "create a folder, beneath the folder at parent-path if given",
+		nargs:   [2]int{1, 2},
+		org:     true,
+		run:     runCreate,
+	},
+	"rm": {
+		args:    "<path>",
+		summary: "delete the folder at path along with its descendants",
+		nargs:   [2]int{1, 1},
+		org:     true,
+		run:     runRm,
+	},
+	"rename": {
+		args:    "<path> <new-name>",
+		summary: "rename the folder at path",
+		nargs:   [2]int{2, 2},
+		org:     true,
+		run:     runRename,
+	},
+	"shell": {
+		summary: "explore and edit the folders of an organisation interactively",
+		org:     true,
+		run:     runShell,
+	},
+	"serve": {
+		summary: "serve the folders as a JSON REST API, saving changes to --file with --write",
+		flags: func(fs *flag.FlagSet, e *env) {
+			fs.StringVar(&e.addr, "addr", "localhost:8080", "address to listen on")
+		},
+		run: runServe,
+	},
+	"validate": {
+		summary: "check the folders form well-formed trees",
+		run:     runValidate,
+	},
+	"generate": {
+		summary: "generate random folders, written to --file with --write",
+		noFile:  true,
+		flags: func(fs *flag.FlagSet, e *env) {
+			e.shape = folder.DefaultGeneratorConfig
+			fs.Int64Var(&e.seed, "seed", 0, "seed to generate the same folders again, a fresh one if 0")
+			fs.IntVar(&e.shape.Orgs, "orgs", e.shape.Orgs, "organisations to share the roots between")
+			fs.IntVar(&e.shape.Roots, "roots", e.shape.Roots, "root folders to generate")
+			fs.IntVar(&e.shape.MinChildren, "min-children", e.shape.MinChildren, "fewest children of each folder above --depth")
+			fs.IntVar(&e.shape.MaxChildren, "max-children", e.shape.MaxChildren, "most children of each folder above --depth")
+			fs.IntVar(&e.shape.MaxDepth, "depth", e.shape.MaxDepth, "depth of the deepest folders")
+			fs.Float64Var(&e.shape.CollisionRate, "collisions", e.shape.CollisionRate, "chance a folder reuses a name from elsewhere in its organisation")
+		},
+		run: runGenerate,
+	},
+	"workload": {
+		summary: "stream a large skewed set of folders to stdout, or to --file with --write as .json or .ndjson",
+		noFile:  true,
+		flags: func(fs *flag.FlagSet, e *env) {
+			e.load = workload.DefaultConfig
+			fs.Int64Var(&e.load.Seed, "seed", e.load.Seed, "seed to generate the same folders again")
+			fs.IntVar(&e.load.Folders, "folders", e.load.Folders, "folders to generate")
+			fs.IntVar(&e.load.Orgs, "orgs", e.load.Orgs, "organisations to share the folders between")
+			fs.Float64Var(&e.load.Skew, "skew", e.load.Skew, "power law exponent of organisation sizes, 0 for even sizes")
+			fs.IntVar(&e.load.MaxTree, "max-tree", e.load.MaxTree, "most folders in one tree")
+			fs.Float64Var(&e.load.DeepRate, "deep", e.load.DeepRate, "share of deep, narrow trees")
+			fs.Float64Var(&e.load.WideRate, "wide", e.load.WideRate, "share of wide, shallow trees")
+			fs.Float64Var(&e.load.DuplicateRate, "duplicates", e.load.DuplicateRate, "chance a folder repeats a name from elsewhere in its organisation")
+		},
+		run: runWorkload,
+	},
+	"benchcmp": {
+		args:    "<old> <new>",
+		summary: "compare two runs of go test -bench, failing if any regressed beyond --threshold",
+		nargs:   [2]int{2, 2},
+		noFile:  true,
+		flags: func(fs *flag.FlagSet, e *env) {
+			fs.Float64Var(&e.threshold, "threshold", 10, "percentage growth in ns/op or allocs/op counted as a regression")
+		},
+		run: runBenchcmp,
+	},
+}
+
+// Run runs the folders command with args, excluding the program name, returning its exit code
+func Run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stderr)
+		return ExitUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "folders: unknown command %q\n\n", args[0])
+		usage(stderr)
+		return ExitUsage
+	}
+
+	e := &env{stdin: stdin, stdout: stdout, stderr: stderr}
+	positional, err := cmd.parse(e, args[0], args[1:])
+	if err == nil {
+		err = cmd.run(e, positional)
+	}
+
+	if err != nil && !errors.Is(err, errUsage) {
+		fmt.Fprintf(stderr, "folders %s: %v\n", args[0], err)
+	}
+	return ExitCode(err)
+}
+
+// ExitCode maps an error from a driver onto the command's exit code
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, errUsage):
+		return ExitUsage
+	case errors.Is(err, folder.ErrFolderNotFound),
+		errors.Is(err, folder.ErrFolderNotInOrg),
+		errors.Is(err, folder.ErrSourceNotFound),
+		errors.Is(err, folder.ErrDestinationNotFound):
+		return ExitNotFound
+	case errors.Is(err, folder.ErrFolderExists):
+		return ExitConflict
+	case errors.Is(err, folder.ErrMoveToSelf),
+		errors.Is(err, folder.ErrMoveToChild),
+		errors.Is(err, folder.ErrMoveToOtherOrg):
+		return ExitInvalidMove
+	case errors.Is(err, folder.ErrInvalidName),
+		errors.Is(err, folder.ErrInvalidPath),
+		errors.Is(err, folder.ErrInvalidConfig),
+		errors.Is(err, folder.ErrInvalidLimits),
+		errors.Is(err, folder.ErrTooManyFolders),
+		errors.Is(err, folder.ErrTooDeep),
+		errors.Is(err, folder.ErrTooManyChildren),
+		errors.Is(err, folder.ErrPathTooLong),
+		errors.Is(err, workload.ErrInvalidConfig),
+		errors.Is(err, errInvalidData):
+		return ExitInvalidInput
+	}
+	return ExitError
+}
+
+// parse reads the flags and arguments of a command, then loads the folder file unless the command runs without one
+func (c *command) parse(e *env, name string, args []string) ([]string, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&e.file, "file", DefaultFile, "folder file to read, as .json, .ndjson, .csv or .yaml")
+	org := fs.String("org", "", "organisation ID to operate within")
+	fs.StringVar(&e.format, "format", "", "output format: json, table or tree")
+	fs.BoolVar(&e.write, "write", false, "write the resulting folders back to --file")
+	fs.IntVar(&e.limits.MaxFolders, "limit-folders", 0, "most folders in an organisation, or 0 for no limit")
+	fs.IntVar(&e.limits.MaxDepth, "limit-depth", 0, "depth of the deepest folders, roots being at 1, or 0 for no limit")
+	fs.IntVar(&e.limits.MaxChildren, "limit-children", 0, "most children of a folder, or 0 for no limit")
+	fs.IntVar(&e.limits.MaxPathLength, "limit-path-length", 0, "longest path in bytes, or 0 for no limit")
+	if c.flags != nil {
+		c.flags(fs, e)
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "usage: folders %s [flags] %s\n\n%s\n\n", name, c.args, c.summary)
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseInterleaved(fs, args)
+	if err != nil {
+		return nil, errUsage
+	}
+
+	if len(positional) < c.nargs[0] || len(positional) > c.nargs[1] {
+		fs.Usage()
+		return nil, errUsage
+	}
+
+	switch e.format {
+	case "", "json", "table", "tree":
+	default:
+		fmt.Fprintf(e.stderr, "folders %s: unknown format %q\n", name, e.format)
+		return nil, errUsage
+	}
+
+	if *org != "" {
+		if e.org, err = uuid.FromString(*org); err != nil {
+			fmt.Fprintf(e.stderr, "folders %s: invalid --org: %v\n", name, err)
+			return nil, errUsage
+		}
+	} else if c.org {
+		fmt.Fprintf(e.stderr, "folders %s: --org is required\n", name)
+		return nil, errUsage
+	}
+
+	if c.noFile {
+		return positional, nil
+	}
+
+	if e.folders, err = loadFolders(e.file); err != nil {
+		return positional, err
+	}
+
+	// Folders beyond the limits are rejected as they are read, as by any other import
+	e.driver, err = folder.NewLimitedDriver(folder.NewDriver, e.folders, e.limits)
+	return positional, err
+}
+
+// parseInterleaved parses flags which may come before, between or after positional arguments
+func parseInterleaved(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: folders <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "folders <command> -h" for the flags of a command.`)
+	fmt.Fprintf(w, "Exit codes: %s\n", strings.Join([]string{
+		"0 ok", "1 error", "2 usage", "3 not found", "4 conflict", "5 invalid move", "6 invalid input",
+	}, ", "))
+}
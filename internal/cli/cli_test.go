@@ -0,0 +1,286 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/internal/cli"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validOrg = "c59cc5c1-9b81-4d00-95e3-22c6efdaf134"
+
+const otherOrg = "5e35ff8f-21dd-4ed5-b861-ba93dbcdadc3"
+
+var sampleFolders = []folder.Folder{
+	{Name: "alpha", OrgId: uuid.FromStringOrNil(validOrg), Paths: "alpha"},
+	{Name: "bravo", OrgId: uuid.FromStringOrNil(validOrg), Paths: "alpha.bravo"},
+	{Name: "charlie", OrgId: uuid.FromStringOrNil(validOrg), Paths: "alpha.bravo.charlie"},
+	{Name: "delta", OrgId: uuid.FromStringOrNil(validOrg), Paths: "alpha.delta"},
+	{Name: "echo", OrgId: uuid.FromStringOrNil(validOrg), Paths: "echo"},
+	{Name: "foxtrot", OrgId: uuid.FromStringOrNil(otherOrg), Paths: "foxtrot"},
+}
+
+// writeSample writes the sample folders to a file in a fresh directory, returning its path
+func writeSample(t *testing.T, name string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, folder.MarshalJson(sampleFolders), 0o644))
+	return path
+}
+
+// run runs the command, returning its exit code and output
+func run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := cli.Run(args, strings.NewReader(""), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func Test_cli_Output(t *testing.T) {
+	t.Parallel()
+
+	file := writeSample(t, "folders.json")
+
+	tests := [...]struct {
+		testName string
+		args     []string
+		want     string
+	}{
+		{
+			testName: "Tree of one organisation",
+			args:     []string{"tree", "--file", file, "--org", validOrg},
+			want: validOrg + "\n" +
+				"├── alpha\n" +
+				"│   ├── bravo\n" +
+				"│   │   └── charlie\n" +
+				"│   └── delta\n" +
+				"└── echo\n",
+		},
+		{
+			testName: "Children as a table",
+			args:     []string{"children", "bravo", "--file", file, "--org", validOrg},
+			want: "NAME     ORG_ID                                PATHS\n" +
+				"charlie  " + validOrg + "  alpha.bravo.charlie\n",
+		},
+		{
+			testName: "Listing of one organisation as JSON",
+			args:     []string{"ls", "--file", file, "--org", otherOrg, "--format", "json"},
+			want:     string(folder.MarshalJson(sampleFolders[5:])) + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			code, stdout, stderr := run(tt.args...)
+
+			assert.Equal(t, cli.ExitOK, code, stderr)
+			assert.Equal(t, tt.want, stdout, tt.testName)
+		})
+	}
+}
+
+func Test_cli_ExitCodes(t *testing.T) {
+	t.Parallel()
+
+	file := writeSample(t, "folders.json")
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, folder.MarshalJson(sampleFolders[1:]), 0o644))
+
+	tests := [...]struct {
+		testName string
+		args     []string
+		want     int
+	}{
+		{testName: "No command", args: []string{}, want: cli.ExitUsage},
+		{testName: "Unknown command", args: []string{"frobnicate"}, want: cli.ExitUsage},
+		{testName: "Missing argument", args: []string{"rm", "--file", file, "--org", validOrg}, want: cli.ExitUsage},
+		{testName: "Missing --org", args: []string{"children", "alpha", "--file", file}, want: cli.ExitUsage},
+		{testName: "Invalid --org", args: []string{"ls", "--file", file, "--org", "nope"}, want: cli.ExitUsage},
+		{testName: "Unknown format", args: []string{"ls", "--file", file, "--format", "xml"}, want: cli.ExitUsage},
+		{testName: "Missing file", args: []string{"ls", "--file", file + ".missing"}, want: cli.ExitError},
+		{testName: "Folder not found", args: []string{"children", "zulu", "--file", file, "--org", validOrg}, want: cli.ExitNotFound},
+		{testName: "Folder in another organisation", args: []string{"children", "foxtrot", "--file", file, "--org", validOrg}, want: cli.ExitNotFound},
+		{testName: "Folder already exists", args: []string{"create", "bravo", "alpha", "--file", file, "--org", validOrg}, want: cli.ExitConflict},
+		{testName: "Move to a child", args: []string{"move", "alpha", "charlie", "--file", file}, want: cli.ExitInvalidMove},
+		{testName: "Move to another organisation", args: []string{"move", "alpha", "foxtrot", "--file", file}, want: cli.ExitInvalidMove},
+		{testName: "Invalid name", args: []string{"rename", "echo", "e.cho", "--file", file, "--org", validOrg}, want: cli.ExitInvalidInput},
+		{testName: "Invalid folders", args: []string{"validate", "--file", invalid}, want: cli.ExitInvalidInput},
+		{testName: "Folders beyond a limit", args: []string{"ls", "--file", file, "--limit-depth", "2"}, want: cli.ExitInvalidInput},
+		{testName: "Invalid limit", args: []string{"ls", "--file", file, "--limit-folders", "-1"}, want: cli.ExitInvalidInput},
+		{testName: "Mutation beyond a limit", args: []string{"create", "golf", "alpha.bravo.charlie", "--file", file, "--org", validOrg, "--limit-depth", "3"}, want: cli.ExitInvalidInput},
+		{testName: "Unusable address to serve on", args: []string{"serve", "--file", file, "--addr", "localhost:-1"}, want: cli.ExitError},
+		{testName: "Valid folders", args: []string{"validate", "--file", file}, want: cli.ExitOK},
+		{testName: "Mutation within the limits", args: []string{"create", "golf", "alpha.bravo", "--file", file, "--org", validOrg, "--limit-depth", "3"}, want: cli.ExitOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			code, _, _ := run(tt.args...)
+			assert.Equal(t, tt.want, code, tt.testName)
+		})
+	}
+}
+
+func Test_cli_UsageMessages(t *testing.T) {
+	t.Parallel()
+
+	file := writeSample(t, "folders.json")
+
+	tests := [...]struct {
+		testName string
+		args     []string
+		want     string
+	}{
+		{testName: "Missing --org", args: []string{"children", "alpha", "--file", file}, want: "folders children: --org is required\n"},
+		{testName: "Invalid --org", args: []string{"ls", "--file", file, "--org", "nope"}, want: "folders ls: invalid --org: "},
+		{testName: "Unknown format", args: []string{"ls", "--file", file, "--format", "xml"}, want: "folders ls: unknown format \"xml\"\n"},
+		{testName: "Move by path without --org", args: []string{"move", "--path", "alpha", "echo", "--file", file}, want: "folders move: --path requires --org\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			code, stdout, stderr := run(tt.args...)
+
+			assert.Equal(t, cli.ExitUsage, code, tt.testName)
+			assert.Empty(t, stdout, tt.testName)
+			assert.Contains(t, stderr, tt.want, tt.testName)
+		})
+	}
+}
+
+func Test_cli_Mutations(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "folders.yaml")
+	var buf bytes.Buffer
+	require.NoError(t, folder.WriteYAML(&buf, sampleFolders))
+	require.NoError(t, os.WriteFile(file, buf.Bytes(), 0o644))
+
+	code, _, stderr := run("move", "bravo", "echo", "--file", file)
+	require.Equal(t, cli.ExitOK, code, stderr)
+	unchanged, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, buf.String(), string(unchanged), "Without --write the file is untouched")
+
+	steps := [][]string{
+		{"move", "bravo", "echo", "--write"},
+		{"create", "golf", "echo.bravo", "--org", validOrg, "--write"},
+		{"rename", "alpha.delta", "hotel", "--org", validOrg, "--write"},
+		{"rm", "echo.bravo.charlie", "--org", validOrg, "--write"},
+		{"move", "--path", "echo.bravo", "", "--org", validOrg, "--write"},
+	}
+	for _, step := range steps {
+		code, _, stderr := run(append(step, "--file", file)...)
+		require.Equal(t, cli.ExitOK, code, "%v: %s", step, stderr)
+	}
+
+	code, stdout, stderr := run("tree", "--file", file)
+	assert.Equal(t, cli.ExitOK, code, stderr)
+	assert.Equal(t, validOrg+"\n"+
+		"├── alpha\n"+
+		"│   └── hotel\n"+
+		"├── echo\n"+
+		"└── bravo\n"+
+		"    └── golf\n"+
+		otherOrg+"\n"+
+		"└── foxtrot\n", stdout)
+}
+
+func Test_cli_Mutations_ReplaceFile(t *testing.T) {
+	t.Parallel()
+
+	file := writeSample(t, "folders.json")
+	require.NoError(t, os.Chmod(file, 0o600))
+
+	code, _, stderr := run("rm", "echo", "--org", validOrg, "--write", "--file", file)
+	require.Equal(t, cli.ExitOK, code, stderr)
+
+	// The folders are written alongside and renamed over the file, keeping its mode
+	entries, err := os.ReadDir(filepath.Dir(file))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "Nothing is left behind beside the file")
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	b, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(folder.MarshalJson(append(sampleFolders[:4:4], sampleFolders[5]))), string(b))
+}
+
+func Test_cli_Generate(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "generated.csv")
+
+	code, _, stderr := run("generate", "--file", file, "--write")
+	require.Equal(t, cli.ExitOK, code, stderr)
+
+	code, stdout, stderr := run("validate", "--file", file)
+	assert.Equal(t, cli.ExitOK, code, stderr)
+	assert.Contains(t, stdout, "folders are valid")
+}
+
+func Test_cli_Generate_Seed(t *testing.T) {
+	t.Parallel()
+
+	args := []string{"generate", "--seed", "42", "--orgs", "1", "--roots", "2", "--depth", "3", "--collisions", "0.5"}
+	code, first, stderr := run(args...)
+	require.Equal(t, cli.ExitOK, code, stderr)
+	code, second, stderr := run(args...)
+	require.Equal(t, cli.ExitOK, code, stderr)
+	assert.Equal(t, first, second, "The same seed generates the same folders")
+
+	code, _, stderr = run("generate", "--roots", "1")
+	require.Equal(t, cli.ExitOK, code, stderr)
+	assert.Contains(t, stderr, "generating with --seed ", "A fresh seed is reported")
+
+	code, _, _ = run("generate", "--min-children", "3", "--max-children", "2")
+	assert.Equal(t, cli.ExitInvalidInput, code)
+}
+
+func Test_cli_Workload(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "workload.ndjson")
+
+	code, _, stderr := run("workload", "--file", file, "--write", "--folders", "5000", "--orgs", "20", "--seed", "3")
+	require.Equal(t, cli.ExitOK, code, stderr)
+	assert.Contains(t, stderr, "5000 folders in 20 organisations")
+
+	code, stdout, stderr := run("validate", "--file", file)
+	assert.Equal(t, cli.ExitOK, code, stderr)
+	assert.Equal(t, "5000 folders are valid\n", stdout)
+
+	code, stdout, stderr = run("workload", "--folders", "100", "--orgs", "2", "--seed", "3")
+	require.Equal(t, cli.ExitOK, code, stderr)
+	folders := []folder.Folder{}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &folders))
+	assert.Len(t, folders, 100)
+
+	code, _, _ = run("workload", "--deep", "0.7", "--wide", "0.7")
+	assert.Equal(t, cli.ExitInvalidInput, code)
+}
+
+func Test_cli_Benchcmp(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	old, new := filepath.Join(dir, "old.txt"), filepath.Join(dir, "new.txt")
+	require.NoError(t, os.WriteFile(old, []byte("Benchmark_folder_MoveFolder/bushy/1000-8 13000 100000 ns/op 122000 B/op 20 allocs/op\n"), 0o644))
+	require.NoError(t, os.WriteFile(new, []byte("Benchmark_folder_MoveFolder/bushy/1000-8 13000 115000 ns/op 122000 B/op 20 allocs/op\n"), 0o644))
+
+	code, stdout, stderr := run("benchcmp", old, new)
+	assert.Equal(t, cli.ExitError, code)
+	assert.Contains(t, stdout, "+15.0%")
+	assert.Contains(t, stderr, "benchmarks regressed: 1 by more than 10%")
+
+	code, stdout, stderr = run("benchcmp", "--threshold", "20", old, new)
+	assert.Equal(t, cli.ExitOK, code, stderr)
+	assert.NotContains(t, stdout, "REGRESSED")
+}
@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	folderhttp "github.com/georgechieng-sc/interns-2022/folder/http"
+	"github.com/georgechieng-sc/interns-2022/folder/workload"
+	"github.com/georgechieng-sc/interns-2022/internal/benchcmp"
+	"github.com/gofrs/uuid"
+	"github.com/lucasepe/codename"
+)
+
+func runLs(e *env, args []string) error {
+	return e.print(e.scoped(e.folders), "table")
+}
+
+func runTree(e *env, args []string) error {
+	return e.print(e.scoped(e.folders), "tree")
+}
+
+func runChildren(e *env, args []string) error {
+	children, err := e.driver.GetAllChildFolders(e.org, args[0])
+	if err != nil {
+		return err
+	}
+	return e.print(children, "table")
+}
+
+func runMove(e *env, args []string) error {
+	if !e.byPath {
+		return e.commit(e.driver.MoveFolder(args[0], args[1]))
+	}
+
+	if e.org == uuid.Nil {
+		fmt.Fprintln(e.stderr, "folders move: --path requires --org")
+		return errUsage
+	}
+	return e.commit(e.driver.MoveFolderByPath(e.org, args[0], args[1]))
+}
+
+func runCreate(e *env, args []string) error {
+	var parent string = ""
+	if len(args) > 1 {
+		parent = args[1]
+	}
+	return e.commit(e.driver.CreateFolder(e.org, args[0], parent))
+}
+
+func runRm(e *env, args []string) error {
+	return e.commit(e.driver.DeleteFolder(e.org, args[0]))
+}
+
+func runRename(e *env, args []string) error {
+	return e.commit(e.driver.RenameFolder(e.org, args[0], args[1]))
+}
+
+func runServe(e *env, args []string) error {
+	// Requests are served in parallel, so need a driver safe for concurrent use
+	d, err := folder.NewLimitedDriver(folder.NewConcurrentDriver, e.folders, e.limits)
+	if err != nil {
+		return err
+	}
+
+	s := folderhttp.NewServer(d)
+	if e.write {
+		s.OnChange = func(folders []folder.Folder) error {
+			return saveFolders(e.file, folders)
+		}
+	}
+
+	fmt.Fprintf(e.stderr, "serving %d folders from %s on http://%s\n", len(e.folders), e.file, e.addr)
+	return http.ListenAndServe(e.addr, s)
+}
+
+func runValidate(e *env, args []string) error {
+	problems := folder.Validate(e.folders)
+	for _, p := range problems {
+		fmt.Fprintln(e.stdout, p)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %d problems", errInvalidData, len(problems))
+	}
+	fmt.Fprintf(e.stdout, "%d folders are valid\n", len(e.folders))
+	return nil
+}
+
+func runGenerate(e *env, args []string) error {
+	if e.seed == 0 {
+		seed, err := codename.NewCryptoSeed()
+		if err != nil {
+			return err
+		}
+		e.seed = seed
+		fmt.Fprintf(e.stderr, "generating with --seed %d\n", e.seed)
+	}
+
+	g, err := folder.NewGenerator(e.seed, e.shape)
+	if err != nil {
+		return err
+	}
+
+	folders := g.Generate()
+	if e.write {
+		return saveFolders(e.file, folders)
+	}
+	return e.print(folders, "json")
+}
+
+func runWorkload(e *env, args []string) error {
+	if !e.write {
+		summary, err := workload.Generate(e.stdout, workload.JSON, e.load)
+		if err == nil {
+			fmt.Fprintln(e.stdout)
+			reportWorkload(e, summary)
+		}
+		return err
+	}
+
+	var format workload.Format = workload.JSON
+	if strings.ToLower(filepath.Ext(e.file)) == ".ndjson" {
+		format = workload.NDJSON
+	}
+
+	var summary workload.Summary
+	err := writeFile(e.file, func(w io.Writer) error {
+		var err error
+		summary, err = workload.Generate(w, format, e.load)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	reportWorkload(e, summary)
+	return nil
+}
+
+func reportWorkload(e *env, s workload.Summary) {
+	fmt.Fprintf(e.stderr, "%d folders in %d organisations, the largest with %d; %d trees up to %d deep\n",
+		s.Folders, s.Orgs, s.LargestOrg, s.Trees, s.MaxDepth)
+}
+
+func runBenchcmp(e *env, args []string) error {
+	runs := [2][]benchcmp.Result{}
+	for i, path := range args {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		runs[i], err = benchcmp.Parse(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("read %s: %w", path, err)
+		}
+	}
+
+	comparisons := benchcmp.Compare(runs[0], runs[1])
+	if err := benchcmp.Write(e.stdout, comparisons, e.threshold); err != nil {
+		return err
+	}
+
+	regressed := 0
+	for _, c := range comparisons {
+		if c.Regressed(e.threshold) {
+			regressed++
+		}
+	}
+	if regressed > 0 {
+		return fmt.Errorf("%w: %d by more than %g%%", errRegressed, regressed, e.threshold)
+	}
+	return nil
+}
+
+// commit prints the folders left by a mutation, and writes them back if asked to
+func (e *env) commit(folders []folder.Folder, err error) error {
+	if err != nil {
+		return err
+	}
+
+	e.folders = folders
+	if e.write {
+		if err := saveFolders(e.file, folders); err != nil {
+			return err
+		}
+	}
+	return e.print(e.scoped(folders), "table")
+}
+
+// scoped filters folders to the organisation given by --org, if any
+func (e *env) scoped(folders []folder.Folder) []folder.Folder {
+	if e.org == uuid.Nil {
+		return folders
+	}
+	return folder.NewDriver(folders).GetFoldersByOrgID(e.org)
+}
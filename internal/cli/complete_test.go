@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_shell_complete(t *testing.T) {
+	t.Parallel()
+
+	var validOrgId = uuid.FromStringOrNil("c59cc5c1-9b81-4d00-95e3-22c6efdaf134")
+	folders := []folder.Folder{
+		{Name: "alpha", OrgId: validOrgId, Paths: "alpha"},
+		{Name: "bravo", OrgId: validOrgId, Paths: "alpha.bravo"},
+		{Name: "beta", OrgId: validOrgId, Paths: "alpha.beta"},
+		{Name: "echo", OrgId: validOrgId, Paths: "echo"},
+	}
+	s := &shell{e: &env{org: validOrgId}, driver: folder.NewDriver(folders), folders: folders}
+
+	tests := [...]struct {
+		testName string
+		line     string
+		cwd      string
+		want     []string
+	}{
+		{testName: "Command names", line: "c", want: []string{"cd ", "commit "}},
+		{testName: "Labels at the root", line: "cd ", want: []string{"alpha/", "echo/"}},
+		{testName: "Labels of a nested folder", line: "ls alpha/b", want: []string{"alpha/beta/", "alpha/bravo/"}},
+		{testName: "Labels relative to the current folder", line: "mv br", cwd: "alpha", want: []string{"bravo/"}},
+		{testName: "Absolute path from a nested folder", line: "cd /e", cwd: "alpha", want: []string{"/echo/"}},
+		{testName: "No match", line: "cd zulu", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			s.cwd = tt.cwd
+			head, completions, tail := s.complete(tt.line+" tail", len(tt.line))
+
+			assert.Equal(t, tt.line[:len(tt.line)-len(lastWord(tt.line))], head, tt.testName)
+			assert.Equal(t, tt.want, completions, tt.testName)
+			assert.Equal(t, " tail", tail, tt.testName)
+		})
+	}
+}
+
+// lastWord returns the text after the last space
+func lastWord(line string) string {
+	for i := len(line) - 1; i >= 0; i-- {
+		if line[i] == ' ' {
+			return line[i+1:]
+		}
+	}
+	return line
+}
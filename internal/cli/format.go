@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/gofrs/uuid"
+)
+
+// print writes folders in the format given by --format, or fallback if there was none
+func (e *env) print(folders []folder.Folder, fallback string) error {
+	var format string = e.format
+	if format == "" {
+		format = fallback
+	}
+
+	switch format {
+	case "json":
+		_, err := fmt.Fprintf(e.stdout, "%s\n", folder.MarshalJson(folders))
+		return err
+	case "tree":
+		return writeTree(e.stdout, folders)
+	}
+	return writeTable(e.stdout, folders)
+}
+
+// writeTable writes folders as aligned columns
+func writeTable(w io.Writer, folders []folder.Folder) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tORG_ID\tPATHS")
+	for _, f := range folders {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", f.Name, f.OrgId, f.Paths)
+	}
+	return tw.Flush()
+}
+
+// writeTree draws the folders of each organisation as a tree.
+// Folders whose parent is missing are drawn as roots, labelled with their whole path.
+func writeTree(w io.Writer, folders []folder.Folder) error {
+	orgs := []uuid.UUID{}
+	orgFolders := map[uuid.UUID][]folder.Folder{}
+	for _, f := range folders {
+		if _, ok := orgFolders[f.OrgId]; !ok {
+			orgs = append(orgs, f.OrgId)
+		}
+		orgFolders[f.OrgId] = append(orgFolders[f.OrgId], f)
+	}
+
+	var buf bytes.Buffer
+	for _, org := range orgs {
+		fmt.Fprintln(&buf, org)
+		writeSubtree(&buf, treeChildren(orgFolders[org]), "", "")
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// treeChildren groups one organisation's folders by the path of their parent,
+// where folders without a parent among them are grouped under the empty path
+func treeChildren(folders []folder.Folder) map[string][]folder.Folder {
+	paths := map[string]bool{}
+	for _, f := range folders {
+		paths[f.Paths] = true
+	}
+
+	children := map[string][]folder.Folder{}
+	for _, f := range folders {
+		var parent string = folder.ParentPath(f.Paths)
+		if !paths[parent] {
+			parent = ""
+		}
+		children[parent] = append(children[parent], f)
+	}
+	return children
+}
+
+// writeSubtree draws the children of the folder at path, beneath the given line prefix
+func writeSubtree(w io.Writer, children map[string][]folder.Folder, path string, prefix string) {
+	for i, f := range children[path] {
+		var branch, indent string = "├── ", "│   "
+		if i == len(children[path])-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		var label string = f.Name
+		if path == "" {
+			label = f.Paths
+		}
+		fmt.Fprintf(w, "%s%s%s\n", prefix, branch, label)
+		writeSubtree(w, children, f.Paths, prefix+indent)
+	}
+}
+
+// loadFolders reads a folder file, in the format given by its extension
+func loadFolders(path string) ([]folder.Folder, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return folder.ReadCSV(file, folder.CSVOptions{})
+	case ".yaml", ".yml":
+		return folder.ReadYAML(file)
+	case ".ndjson":
+		return folder.ReadNDJSON(file)
+	}
+
+	folders := []folder.Folder{}
+	if err := json.NewDecoder(file).Decode(&folders); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return folders, nil
+}
+
+// saveFolders writes a folder file, in the format given by its extension
+func saveFolders(path string, folders []folder.Folder) error {
+	return writeFile(path, func(w io.Writer) error {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			return folder.WriteCSV(w, folders, folder.CSVOptions{})
+		case ".yaml", ".yml":
+			return folder.WriteYAML(w, folders)
+		case ".ndjson":
+			return folder.WriteNDJSON(w, folders)
+		}
+		_, err := w.Write(folder.MarshalJson(folders))
+		return err
+	})
+}
+
+// writeFile replaces the file at path with what write writes. It writes to a temporary file alongside
+// and renames it over the original, so a crash or full disk part way leaves the original untouched.
+func writeFile(path string, write func(w io.Writer) error) error {
+	var mode os.FileMode = 0o644
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	// Fails harmlessly once the file has been renamed
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
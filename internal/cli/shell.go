@@ -0,0 +1,375 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/peterh/liner"
+	"golang.org/x/term"
+)
+
+// shellCommands are the commands understood by the shell, along with their help
+var shellCommands = map[string]string{
+	"cd":     "cd [path]           change the current folder, to the root if no path is given",
+	"ls":     "ls [path]           list the folders within a folder",
+	"pwd":    "pwd                 print the current folder",
+	"tree":   "tree [path]         draw the tree beneath a folder",
+	"mv":     "mv <path> <dst>     move a folder beneath dst, where / is the root",
+	"mkdir":  "mkdir <path>        create a folder",
+	"rm":     "rm <path>           delete a folder along with its descendants",
+	"find":   "find <pattern>      find folders beneath the current one by name, as a glob or substring",
+	"commit": "commit              write the changes back to the folder file",
+	"help":   "help                list the commands",
+	"exit":   "exit                leave the shell, asking again if there are uncommitted changes",
+}
+
+// errUncommitted is returned by exit the first time it is run with uncommitted changes
+var errUncommitted = errors.New("there are uncommitted changes, run commit or exit again to discard them")
+
+// shell navigates one organisation's folders, paths within it being written with slashes
+type shell struct {
+	e       *env
+	driver  folder.IStatefulDriver
+	folders []folder.Folder
+	cwd     string
+	dirty   bool
+	warned  bool
+}
+
+func runShell(e *env, args []string) error {
+	s := &shell{e: e, driver: e.driver, folders: e.folders}
+
+	if f, ok := e.stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return s.interactive()
+	}
+	return s.script(e.stdin)
+}
+
+// interactive reads commands from the terminal, with history and tab completion
+func (s *shell) interactive() error {
+	line := liner.NewLiner()
+	defer line.Close()
+	line.SetCtrlCAborts(true)
+	line.SetTabCompletionStyle(liner.TabPrints)
+	line.SetWordCompleter(s.complete)
+
+	fmt.Fprintf(s.e.stdout, "Folders of %s, type help for commands\n", s.e.org)
+	for {
+		input, err := line.Prompt(s.prompt())
+		if err == liner.ErrPromptAborted || err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line.AppendHistory(input)
+
+		quit, err := s.exec(input)
+		if err != nil {
+			fmt.Fprintf(s.e.stderr, "%v\n", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+// script runs commands read line by line, stopping at the first which fails.
+// Exiting with uncommitted changes fails too, so a script never discards its changes silently.
+func (s *shell) script(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		quit, err := s.exec(scanner.Text())
+		if err != nil {
+			return err
+		}
+		if quit {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *shell) prompt() string {
+	var marker string = ""
+	if s.dirty {
+		marker = "*"
+	}
+	return fmt.Sprintf("folders:%s%s> ", display(s.cwd), marker)
+}
+
+// exec runs a line of input, reporting whether the shell should quit
+func (s *shell) exec(input string) (bool, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return false, nil
+	}
+
+	var cmd string = fields[0]
+	args := fields[1:]
+	if _, ok := shellCommands[cmd]; !ok && cmd != "quit" {
+		return false, fmt.Errorf("unknown command %q, type help for commands", cmd)
+	}
+
+	switch cmd {
+	case "exit", "quit":
+		if s.dirty && !s.warned {
+			s.warned = true
+			return false, errUncommitted
+		}
+		return true, nil
+	case "help":
+		s.help()
+		return false, nil
+	case "pwd":
+		fmt.Fprintln(s.e.stdout, display(s.cwd))
+		return false, nil
+	case "commit":
+		if err := saveFolders(s.e.file, s.folders); err != nil {
+			return false, err
+		}
+		s.dirty = false
+		s.warned = false
+		fmt.Fprintf(s.e.stdout, "wrote %d folders to %s\n", len(s.folders), s.e.file)
+		return false, nil
+	}
+
+	var err error
+	switch {
+	case cmd == "cd" && len(args) <= 1:
+		err = s.cd(optional(args))
+	case cmd == "ls" && len(args) <= 1:
+		err = s.ls(optional(args))
+	case cmd == "tree" && len(args) <= 1:
+		err = s.tree(optional(args))
+	case cmd == "find" && len(args) == 1:
+		err = s.find(args[0])
+	case cmd == "mv" && len(args) == 2:
+		err = s.mv(args[0], args[1])
+	case cmd == "mkdir" && len(args) == 1:
+		err = s.mkdir(args[0])
+	case cmd == "rm" && len(args) == 1:
+		err = s.rm(args[0])
+	default:
+		err = fmt.Errorf("usage: %s", shellCommands[cmd])
+	}
+	return false, err
+}
+
+func (s *shell) help() {
+	names := make([]string, 0, len(shellCommands))
+	for name := range shellCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(s.e.stdout, "  %s\n", shellCommands[name])
+	}
+}
+
+func (s *shell) cd(arg string) error {
+	if arg == "" {
+		arg = "/"
+	}
+
+	p, err := s.resolve(arg)
+	if err != nil {
+		return err
+	}
+	if !s.exists(p) {
+		return fmt.Errorf("%s: %w", display(p), folder.ErrFolderNotFound)
+	}
+
+	s.cwd = p
+	return nil
+}
+
+func (s *shell) ls(arg string) error {
+	p, err := s.resolve(arg)
+	if err != nil {
+		return err
+	}
+	if !s.exists(p) {
+		return fmt.Errorf("%s: %w", display(p), folder.ErrFolderNotFound)
+	}
+
+	for _, name := range s.childNames(p) {
+		fmt.Fprintln(s.e.stdout, name)
+	}
+	return nil
+}
+
+func (s *shell) tree(arg string) error {
+	p, err := s.resolve(arg)
+	if err != nil {
+		return err
+	}
+	if !s.exists(p) {
+		return fmt.Errorf("%s: %w", display(p), folder.ErrFolderNotFound)
+	}
+
+	fmt.Fprintln(s.e.stdout, display(p))
+	writeSubtree(s.e.stdout, treeChildren(s.driver.GetFoldersByOrgID(s.e.org)), p, "")
+	return nil
+}
+
+func (s *shell) find(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return err
+	}
+
+	for _, f := range s.driver.GetFoldersByOrgID(s.e.org) {
+		if s.cwd != "" && !folder.IsDescendantPath(s.cwd, f.Paths) {
+			continue
+		}
+
+		matched, _ := path.Match(pattern, f.Name)
+		if matched || !strings.ContainsAny(pattern, "*?[") && strings.Contains(f.Name, pattern) {
+			fmt.Fprintln(s.e.stdout, display(f.Paths))
+		}
+	}
+	return nil
+}
+
+func (s *shell) mv(src string, dst string) error {
+	srcPath, err := s.resolve(src)
+	if err != nil {
+		return err
+	}
+	dstPath, err := s.resolve(dst)
+	if err != nil {
+		return err
+	}
+	return s.mutate(s.driver.MoveFolderByPath(s.e.org, srcPath, dstPath))
+}
+
+func (s *shell) mkdir(arg string) error {
+	p, err := s.resolve(arg)
+	if err != nil {
+		return err
+	}
+
+	var parent string = folder.ParentPath(p)
+	return s.mutate(s.driver.CreateFolder(s.e.org, strings.TrimPrefix(p[len(parent):], "."), parent))
+}
+
+func (s *shell) rm(arg string) error {
+	p, err := s.resolve(arg)
+	if err != nil {
+		return err
+	}
+	return s.mutate(s.driver.DeleteFolder(s.e.org, p))
+}
+
+// mutate keeps the folders left by a successful mutation, which stay uncommitted until commit
+func (s *shell) mutate(folders []folder.Folder, err error) error {
+	if err != nil {
+		return err
+	}
+
+	s.folders = folders
+	s.dirty = true
+	s.warned = false
+
+	// The current folder may have been moved or deleted from under the shell
+	for s.cwd != "" && !s.exists(s.cwd) {
+		s.cwd = folder.ParentPath(s.cwd)
+	}
+	return nil
+}
+
+// complete completes the word under the cursor, as a command if it is the first word and as a path otherwise
+func (s *shell) complete(line string, pos int) (string, []string, string) {
+	var start int = strings.LastIndex(line[:pos], " ") + 1
+	var head, word, tail string = line[:start], line[start:pos], line[pos:]
+
+	candidates := []string{}
+	if strings.TrimSpace(head) == "" {
+		for name := range shellCommands {
+			if strings.HasPrefix(name, word) {
+				candidates = append(candidates, name+" ")
+			}
+		}
+	} else {
+		var dir string = word[:strings.LastIndex(word, "/")+1]
+		var partial string = word[len(dir):]
+		p, _ := s.resolve(dir)
+		for _, name := range s.childNames(p) {
+			if strings.HasPrefix(name, partial) {
+				candidates = append(candidates, dir+name+"/")
+			}
+		}
+	}
+
+	sort.Strings(candidates)
+	return head, candidates, tail
+}
+
+// resolve turns a slash separated path, relative to the current folder unless it starts with a slash,
+// into an ltree path. A label holding a dot is refused, as it would be taken as more than one folder.
+func (s *shell) resolve(arg string) (string, error) {
+	labels := []string{}
+	if !strings.HasPrefix(arg, "/") && s.cwd != "" {
+		labels = strings.Split(s.cwd, ".")
+	}
+
+	for _, label := range strings.Split(arg, "/") {
+		switch label {
+		case "", ".":
+		case "..":
+			if len(labels) > 0 {
+				labels = labels[:len(labels)-1]
+			}
+		default:
+			if strings.Contains(label, ".") {
+				return "", fmt.Errorf("%w: %q", folder.ErrInvalidName, label)
+			}
+			labels = append(labels, label)
+		}
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// exists checks if there is a folder at an ltree path, the empty path being the root
+func (s *shell) exists(p string) bool {
+	if p == "" {
+		return true
+	}
+
+	for _, f := range s.driver.GetFoldersByOrgID(s.e.org) {
+		if f.Paths == p {
+			return true
+		}
+	}
+	return false
+}
+
+// childNames lists the names of the folders directly within the folder at an ltree path
+func (s *shell) childNames(p string) []string {
+	names := []string{}
+	for _, f := range treeChildren(s.driver.GetFoldersByOrgID(s.e.org))[p] {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// display writes an ltree path with slashes, as the shell takes it
+func display(p string) string {
+	return "/" + strings.ReplaceAll(p, ".", "/")
+}
+
+// optional returns the only argument, or empty if there is none
+func optional(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
@@ -0,0 +1,120 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/georgechieng-sc/interns-2022/folder"
+	"github.com/georgechieng-sc/interns-2022/internal/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runShell runs the shell over a script, returning its exit code and output
+func runShell(file string, script string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := cli.Run([]string{"shell", "--file", file, "--org", validOrg}, strings.NewReader(script), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func Test_cli_Shell_Navigation(t *testing.T) {
+	t.Parallel()
+
+	file := writeSample(t, "folders.json")
+
+	tests := [...]struct {
+		testName string
+		script   string
+		want     string
+	}{
+		{
+			testName: "ls at the root",
+			script:   "ls\n",
+			want:     "alpha\necho\n",
+		},
+		{
+			testName: "cd and pwd",
+			script:   "cd alpha/bravo\npwd\ncd ..\npwd\ncd\npwd\n",
+			want:     "/alpha/bravo\n/alpha\n/\n",
+		},
+		{
+			testName: "ls of a relative path",
+			script:   "cd alpha\nls bravo\n",
+			want:     "charlie\n",
+		},
+		{
+			testName: "tree of the current folder",
+			script:   "cd /alpha\ntree\n",
+			want:     "/alpha\n├── bravo\n│   └── charlie\n└── delta\n",
+		},
+		{
+			testName: "find by glob and substring",
+			script:   "find *a\nfind ch\n",
+			want:     "/alpha\n/alpha/delta\n/alpha/bravo/charlie\n/echo\n",
+		},
+		{
+			testName: "find beneath the current folder",
+			script:   "cd alpha\nfind alpha\n",
+			want:     "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			code, stdout, stderr := runShell(file, tt.script)
+
+			assert.Equal(t, cli.ExitOK, code, stderr)
+			assert.Equal(t, tt.want, stdout, tt.testName)
+		})
+	}
+}
+
+func Test_cli_Shell_Errors(t *testing.T) {
+	t.Parallel()
+
+	file := writeSample(t, "folders.json")
+
+	tests := [...]struct {
+		testName string
+		script   string
+		want     int
+	}{
+		{testName: "Unknown command", script: "frobnicate\n", want: cli.ExitError},
+		{testName: "Wrong number of arguments", script: "mv alpha\n", want: cli.ExitError},
+		{testName: "cd to a missing folder", script: "cd zulu\n", want: cli.ExitNotFound},
+		{testName: "mv into a child", script: "mv alpha alpha/bravo\n", want: cli.ExitInvalidMove},
+		{testName: "mkdir of an existing folder", script: "mkdir alpha/delta\n", want: cli.ExitConflict},
+		{testName: "mkdir with a dot in a label", script: "mkdir alpha.kilo\n", want: cli.ExitInvalidInput},
+		{testName: "cd with a dot in a label", script: "cd alpha.bravo\n", want: cli.ExitInvalidInput},
+		{testName: "exit with uncommitted changes", script: "rm echo\nexit\n", want: cli.ExitError},
+		{testName: "exit after commit", script: "rm echo\ncommit\nexit\n", want: cli.ExitOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			code, _, _ := runShell(file, tt.script)
+			assert.Equal(t, tt.want, code, tt.testName)
+		})
+	}
+}
+
+func Test_cli_Shell_Commit(t *testing.T) {
+	t.Parallel()
+
+	file := writeSample(t, "folders.json")
+
+	code, stdout, stderr := runShell(file, "cd alpha/bravo\nmv /alpha/bravo /echo\npwd\nmkdir /echo/bravo/kilo\nrm /alpha/delta\ncommit\nexit\n")
+	require.Equal(t, cli.ExitOK, code, stderr)
+	assert.Equal(t, "/alpha\nwrote 6 folders to "+file+"\n", stdout, "Current folder follows a move from under it")
+
+	b, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(folder.MarshalJson([]folder.Folder{
+		sampleFolders[0],
+		{Name: "bravo", OrgId: sampleFolders[1].OrgId, Paths: "echo.bravo"},
+		{Name: "charlie", OrgId: sampleFolders[2].OrgId, Paths: "echo.bravo.charlie"},
+		sampleFolders[4],
+		sampleFolders[5],
+		{Name: "kilo", OrgId: sampleFolders[0].OrgId, Paths: "echo.bravo.kilo"},
+	})), string(b))
+}
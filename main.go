@@ -1,22 +1,11 @@
 package main
 
 import (
-	"fmt"
+	"os"
 
-	"github.com/georgechieng-sc/interns-2022/folder"
-	"github.com/gofrs/uuid"
+	"github.com/georgechieng-sc/interns-2022/internal/cli"
 )
 
 func main() {
-	orgID := uuid.FromStringOrNil(folder.DefaultOrgID)
-
-	res := folder.GetAllFolders()
-
-	// example usage
-	folderDriver := folder.NewDriver(res)
-	orgFolder := folderDriver.GetFoldersByOrgID(orgID)
-
-	folder.PrettyPrint(res)
-	fmt.Printf("\n Folders for orgID: %s", orgID)
-	folder.PrettyPrint(orgFolder)
+	os.Exit(cli.Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }